@@ -0,0 +1,57 @@
+package nginxconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	"github.com/paketo-buildpacks/web-servers/internal/docroot"
+	"github.com/paketo-buildpacks/web-servers/internal/serverconf"
+)
+
+// Build writes nginx.conf and mime.types to the application source, serving
+// the frontend's build output. It runs after the frontend has been built and
+// before the nginx buildpack reads the configuration.
+func Build(logger scribe.Emitter) packit.BuildFunc {
+	return func(context packit.BuildContext) (packit.BuildResult, error) {
+		logger.Title("%s %s", context.BuildpackInfo.Name, context.BuildpackInfo.Version)
+
+		root, reason, err := docroot.Find(context.WorkingDir)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		logger.Process("Generating %s", ConfigFile)
+		logger.Subprocess("Serving %s (%s)", root, reason)
+
+		// Fragments written by earlier buildpacks, and by later ones that find
+		// the includes already in place
+		config := Config{
+			Generator:      context.BuildpackInfo.ID,
+			Root:           root,
+			HTTPIncludes:   []string{serverconf.NginxHTTP.Include()},
+			ServerIncludes: []string{serverconf.NginxServer.Include(), serverconf.NginxHeaders.Include()},
+		}
+
+		content, err := config.Render()
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		err = os.WriteFile(filepath.Join(context.WorkingDir, ConfigFile), content, 0644)
+		if err != nil {
+			return packit.BuildResult{}, fmt.Errorf("failed to write %s: %w", ConfigFile, err)
+		}
+
+		err = os.WriteFile(filepath.Join(context.WorkingDir, MimeTypesFile), mimeTypes, 0644)
+		if err != nil {
+			return packit.BuildResult{}, fmt.Errorf("failed to write %s: %w", MimeTypesFile, err)
+		}
+
+		logger.Break()
+
+		return packit.BuildResult{}, nil
+	}
+}
@@ -0,0 +1,146 @@
+package nginxconfig_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	nginxconfig "github.com/paketo-buildpacks/web-servers/buildpacks/nginx-config"
+	"github.com/paketo-buildpacks/web-servers/internal/docroot"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testBuild(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+		buffer     *bytes.Buffer
+		build      packit.BuildFunc
+		buildCtx   packit.BuildContext
+	)
+
+	it.Before(func() {
+		workingDir = t.TempDir()
+		buffer = bytes.NewBuffer(nil)
+
+		build = nginxconfig.Build(scribe.NewEmitter(buffer))
+		buildCtx = packit.BuildContext{
+			BuildpackInfo: packit.Info{
+				ID:      "some-org/nginx-config",
+				Name:    "Some Buildpack",
+				Version: "some-version",
+			},
+			WorkingDir: workingDir,
+		}
+	})
+
+	for _, dir := range docroot.OutputDirectories {
+		dir := dir
+
+		context("when the build output is in "+dir, func() {
+			it.Before(func() {
+				Expect(os.MkdirAll(filepath.Join(workingDir, dir), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, dir, "index.html"), nil, 0600)).To(Succeed())
+			})
+
+			it("writes nginx.conf serving that directory", func() {
+				result, err := build(buildCtx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(Equal(packit.BuildResult{}))
+
+				config, err := os.ReadFile(filepath.Join(workingDir, "nginx.conf"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(config)).To(ContainSubstring("root " + dir + ";"))
+				Expect(string(config)).To(ContainSubstring("listen {{port}};"))
+				Expect(string(config)).To(ContainSubstring("try_files $uri $uri/ /index.html;"))
+				Expect(string(config)).To(ContainSubstring("# Generated by the some-org/nginx-config buildpack."))
+				Expect(string(config)).To(ContainSubstring("  port_in_redirect off; # Ensure that redirects don't include the internal container PORT - 8080\n  include web-servers/nginx/http/*.conf;\n"))
+				Expect(string(config)).To(ContainSubstring("    index index.html index.htm;\n    include web-servers/nginx/server/*.conf;\n    include web-servers/nginx/headers/*.conf;\n"))
+
+				Expect(filepath.Join(workingDir, "mime.types")).To(BeARegularFile())
+
+				Expect(buffer.String()).To(ContainSubstring("Some Buildpack some-version"))
+				Expect(buffer.String()).To(ContainSubstring("Generating nginx.conf"))
+				Expect(buffer.String()).To(ContainSubstring("Serving " + dir + " (" + dir + "/index.html was found)"))
+			})
+		})
+	}
+
+	context("when more than one output directory exists", func() {
+		it.Before(func() {
+			for _, dir := range []string{"dist", "out"} {
+				Expect(os.MkdirAll(filepath.Join(workingDir, dir), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, dir, "index.html"), nil, 0600)).To(Succeed())
+			}
+		})
+
+		it("serves the first in order of precedence", func() {
+			_, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+
+			config, err := os.ReadFile(filepath.Join(workingDir, "nginx.conf"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(config)).To(ContainSubstring("root dist;"))
+		})
+	})
+
+	context("when BP_WEB_SERVER_ROOT is set", func() {
+		it.Before(func() {
+			t.Setenv("BP_WEB_SERVER_ROOT", "public/site")
+			Expect(os.MkdirAll(filepath.Join(workingDir, "public", "site"), os.ModePerm)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(workingDir, "build"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(workingDir, "build", "index.html"), nil, 0600)).To(Succeed())
+		})
+
+		it("serves that directory", func() {
+			_, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+
+			config, err := os.ReadFile(filepath.Join(workingDir, "nginx.conf"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(config)).To(ContainSubstring("root public/site;"))
+			Expect(buffer.String()).To(ContainSubstring("Serving public/site (BP_WEB_SERVER_ROOT is set)"))
+		})
+
+		context("as an absolute path inside the app", func() {
+			it.Before(func() {
+				t.Setenv("BP_WEB_SERVER_ROOT", filepath.Join(workingDir, "public", "site"))
+			})
+
+			it("serves it relative to the app", func() {
+				_, err := build(buildCtx)
+				Expect(err).NotTo(HaveOccurred())
+
+				config, err := os.ReadFile(filepath.Join(workingDir, "nginx.conf"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(config)).To(ContainSubstring("root public/site;"))
+			})
+		})
+	})
+
+	context("failure cases", func() {
+		context("when there is no build output", func() {
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError("failed to find build output: none of build, dist, out contains index.html; set BP_WEB_SERVER_ROOT to the directory to serve"))
+			})
+		})
+
+		context("when BP_WEB_SERVER_ROOT does not exist", func() {
+			it.Before(func() {
+				t.Setenv("BP_WEB_SERVER_ROOT", "missing")
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(`BP_WEB_SERVER_ROOT is set to "missing" but the directory does not exist`))
+			})
+		})
+	})
+}
@@ -0,0 +1,44 @@
+package nginxconfig
+
+import (
+	"bytes"
+	_ "embed"
+	"text/template"
+)
+
+//go:embed assets/nginx.conf
+var configTemplate string
+
+//go:embed assets/mime.types
+var mimeTypes []byte
+
+// Config holds the values that are rendered into the generated nginx.conf.
+type Config struct {
+	// Generator is the name of the buildpack, recorded in a header comment.
+	Generator string
+
+	// Root is the directory served, relative to the application source.
+	Root string
+
+	// HTTPIncludes and ServerIncludes are configuration fragments, relative
+	// to the application source, included in the http and server blocks.
+	HTTPIncludes   []string
+	ServerIncludes []string
+}
+
+// Render returns the nginx.conf for the config. The listen port is left as
+// the {{port}} placeholder that the nginx buildpack fills in at launch.
+func (c Config) Render() ([]byte, error) {
+	tmpl, err := template.New(ConfigFile).Parse(configTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	err = tmpl.Execute(buffer, c)
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
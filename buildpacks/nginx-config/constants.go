@@ -0,0 +1,8 @@
+package nginxconfig
+
+// ConfigFile is the NGINX configuration that the nginx buildpack reads from
+// the application source, and that this buildpack generates when it is absent.
+const ConfigFile = "nginx.conf"
+
+// MimeTypesFile is included by the generated configuration.
+const MimeTypesFile = "mime.types"
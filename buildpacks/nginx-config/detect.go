@@ -0,0 +1,62 @@
+package nginxconfig
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/fs"
+	webserverselector "github.com/paketo-buildpacks/web-servers/buildpacks/web-server-selector"
+)
+
+// Detect passes for JavaScript frontends that do not ship any web server
+// configuration, and requires nginx at launch so that the nginx buildpack,
+// which only provides nginx when nginx.conf is absent, joins the group.
+// Apps configured for another server, or with BP_WEB_SERVER naming another
+// server, are left to the order groups for that server. With BP_WEB_SERVER
+// set to nginx, the nginx buildpack generates its own configuration, so this
+// buildpack stands aside.
+func Detect() packit.DetectFunc {
+	return func(context packit.DetectContext) (packit.DetectResult, error) {
+		if name, ok := os.LookupEnv("BP_WEB_SERVER"); ok && name != "" {
+			if name == "nginx" {
+				return packit.DetectResult{}, packit.Fail.WithMessage("BP_WEB_SERVER is set to %q, so the nginx buildpack generates nginx.conf", name)
+			}
+
+			return packit.DetectResult{}, packit.Fail.WithMessage("BP_WEB_SERVER is set to %q", name)
+		}
+
+		for _, server := range webserverselector.Servers {
+			exists, err := fs.Exists(filepath.Join(context.WorkingDir, server.ConfigFile))
+			if err != nil {
+				return packit.DetectResult{}, err
+			}
+
+			if exists {
+				return packit.DetectResult{}, packit.Fail.WithMessage("%s was found", server.ConfigFile)
+			}
+		}
+
+		exists, err := fs.Exists(filepath.Join(context.WorkingDir, "package.json"))
+		if err != nil {
+			return packit.DetectResult{}, err
+		}
+
+		if !exists {
+			return packit.DetectResult{}, packit.Fail.WithMessage("package.json was not found")
+		}
+
+		return packit.DetectResult{
+			Plan: packit.BuildPlan{
+				Requires: []packit.BuildPlanRequirement{
+					{
+						Name: "nginx",
+						Metadata: map[string]interface{}{
+							"launch": true,
+						},
+					},
+				},
+			},
+		}, nil
+	}
+}
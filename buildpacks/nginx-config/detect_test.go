@@ -0,0 +1,102 @@
+package nginxconfig_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	nginxconfig "github.com/paketo-buildpacks/web-servers/buildpacks/nginx-config"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDetect(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+		detect     packit.DetectFunc
+	)
+
+	it.Before(func() {
+		workingDir = t.TempDir()
+		Expect(os.WriteFile(filepath.Join(workingDir, "package.json"), []byte(`{}`), 0600)).To(Succeed())
+
+		detect = nginxconfig.Detect()
+	})
+
+	it("requires nginx at launch", func() {
+		result, err := detect(packit.DetectContext{
+			WorkingDir: workingDir,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Plan).To(Equal(packit.BuildPlan{
+			Requires: []packit.BuildPlanRequirement{
+				{
+					Name: "nginx",
+					Metadata: map[string]interface{}{
+						"launch": true,
+					},
+				},
+			},
+		}))
+	})
+
+	context("when BP_WEB_SERVER is set to nginx", func() {
+		it.Before(func() {
+			t.Setenv("BP_WEB_SERVER", "nginx")
+		})
+
+		it("fails detection, leaving the configuration to the nginx buildpack", func() {
+			_, err := detect(packit.DetectContext{
+				WorkingDir: workingDir,
+			})
+			Expect(err).To(MatchError(packit.Fail.WithMessage(`BP_WEB_SERVER is set to "nginx", so the nginx buildpack generates nginx.conf`)))
+		})
+	})
+
+	context("when BP_WEB_SERVER names another server", func() {
+		it.Before(func() {
+			t.Setenv("BP_WEB_SERVER", "httpd")
+		})
+
+		it("fails detection", func() {
+			_, err := detect(packit.DetectContext{
+				WorkingDir: workingDir,
+			})
+			Expect(err).To(MatchError(packit.Fail.WithMessage(`BP_WEB_SERVER is set to "httpd"`)))
+		})
+	})
+
+	for _, file := range []string{"nginx.conf", "httpd.conf", "Caddyfile"} {
+		file := file
+
+		context("when the app contains "+file, func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, file), nil, 0600)).To(Succeed())
+			})
+
+			it("fails detection", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).To(MatchError(packit.Fail.WithMessage("%s was found", file)))
+			})
+		})
+	}
+
+	context("when the app has no package.json", func() {
+		it.Before(func() {
+			Expect(os.Remove(filepath.Join(workingDir, "package.json"))).To(Succeed())
+		})
+
+		it("fails detection", func() {
+			_, err := detect(packit.DetectContext{
+				WorkingDir: workingDir,
+			})
+			Expect(err).To(MatchError(packit.Fail.WithMessage("package.json was not found")))
+		})
+	})
+}
@@ -0,0 +1,15 @@
+package nginxconfig_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitNginxConfig(t *testing.T) {
+	suite := spec.New("nginx-config", spec.Report(report.Terminal{}), spec.Sequential())
+	suite("Detect", testDetect)
+	suite("Build", testBuild)
+	suite.Run(t)
+}
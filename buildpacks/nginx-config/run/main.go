@@ -0,0 +1,18 @@
+package main
+
+import (
+	"os"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	nginxconfig "github.com/paketo-buildpacks/web-servers/buildpacks/nginx-config"
+)
+
+func main() {
+	logger := scribe.NewEmitter(os.Stdout).WithLevel(os.Getenv("BP_LOG_LEVEL"))
+
+	packit.Run(
+		nginxconfig.Detect(),
+		nginxconfig.Build(logger),
+	)
+}
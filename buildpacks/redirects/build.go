@@ -0,0 +1,148 @@
+package redirects
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/fs"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	webserverselector "github.com/paketo-buildpacks/web-servers/buildpacks/web-server-selector"
+	"github.com/paketo-buildpacks/web-servers/internal/docroot"
+	"github.com/paketo-buildpacks/web-servers/internal/serverconf"
+)
+
+// Build translates the rule files for the web server in the order group. It
+// runs after the frontend has been built, so the files are read from the
+// build output when there is one, and before the server's buildpack reads
+// its configuration.
+func Build(logger scribe.Emitter) packit.BuildFunc {
+	return func(context packit.BuildContext) (packit.BuildResult, error) {
+		logger.Title("%s %s", context.BuildpackInfo.Name, context.BuildpackInfo.Version)
+
+		// The build output is searched first; apps without one, such as static
+		// sites, serve their source, where detection found the rule files
+		dirs := SourceDirectories
+		if root, _, err := docroot.Find(context.WorkingDir); err == nil {
+			dirs = append([]string{root}, SourceDirectories...)
+		}
+
+		dir, found, err := findRules(context.WorkingDir, dirs)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		if !found {
+			logger.Process("No %s or %s file was found in %s", RedirectsFile, HeadersFile, strings.Join(dirs, ", "))
+			logger.Break()
+			return packit.BuildResult{}, nil
+		}
+
+		redirects, err := readRules(filepath.Join(context.WorkingDir, dir, RedirectsFile), ParseRedirects)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		headers, err := readRules(filepath.Join(context.WorkingDir, dir, HeadersFile), ParseHeaders)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		server, _, err := webserverselector.Selected(context.Plan)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		logger.Process("Translating %s and %s in %s for %s", RedirectsFile, HeadersFile, dir, server.Name)
+		logger.Subprocess("%d redirect rules, %d header rules", len(redirects), len(headers))
+
+		var fragments map[serverconf.Context][]byte
+		switch server.Name {
+		case "nginx":
+			fragments, err = Nginx(context.BuildpackInfo.ID, redirects, headers)
+		case "httpd":
+			var content []byte
+			content, err = Httpd(context.BuildpackInfo.ID, redirects, headers)
+			fragments = map[serverconf.Context][]byte{serverconf.Httpd: content}
+		default:
+			err = fmt.Errorf("%s and %s are not supported for %s", RedirectsFile, HeadersFile, server.Name)
+		}
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		for _, fragmentContext := range slices.Sorted(maps.Keys(fragments)) {
+			err = serverconf.Write(context.WorkingDir, fragmentContext, Fragment, fragments[fragmentContext])
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			logger.Subprocess("Wrote %s/%s/%s.conf", serverconf.Dir, fragmentContext, Fragment)
+		}
+
+		// A generated nginx.conf includes the fragments itself; one provided by
+		// the app has the includes added
+		injected, err := serverconf.Inject(context.WorkingDir, server.Name)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		if injected {
+			logger.Subprocess("Included the fragments in %s", server.ConfigFile)
+		}
+
+		// The configuration the httpd buildpack generates has no includes
+		if server.Name == "httpd" {
+			exists, err := fs.Exists(filepath.Join(context.WorkingDir, server.ConfigFile))
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			if !exists {
+				logger.Subprocess("Warning: the rules only apply to an app that provides %s", server.ConfigFile)
+			}
+		}
+
+		logger.Break()
+
+		return packit.BuildResult{}, nil
+	}
+}
+
+// findRules returns the first of dirs that holds a rule file.
+func findRules(workingDir string, dirs []string) (string, bool, error) {
+	for _, dir := range dirs {
+		for _, file := range []string{RedirectsFile, HeadersFile} {
+			exists, err := fs.Exists(filepath.Join(workingDir, dir, file))
+			if err != nil {
+				return "", false, err
+			}
+
+			if exists {
+				return dir, true, nil
+			}
+		}
+	}
+
+	return "", false, nil
+}
+
+// readRules parses a rule file, treating a missing file as empty.
+func readRules[T any](path string, parse func(io.Reader) ([]T, error)) ([]T, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to open %s: %w", filepath.Base(path), err)
+	}
+	defer file.Close()
+
+	return parse(file)
+}
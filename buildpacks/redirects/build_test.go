@@ -0,0 +1,246 @@
+package redirects_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	"github.com/paketo-buildpacks/web-servers/buildpacks/redirects"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testBuild(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+		buffer     *bytes.Buffer
+		build      packit.BuildFunc
+		buildCtx   packit.BuildContext
+	)
+
+	it.Before(func() {
+		workingDir = t.TempDir()
+		buffer = bytes.NewBuffer(nil)
+
+		Expect(os.MkdirAll(filepath.Join(workingDir, "build"), os.ModePerm)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(workingDir, "build", "index.html"), nil, 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(workingDir, "build", "_redirects"), []byte("/old /new\n"), 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(workingDir, "build", "_headers"), []byte("/*\n  X-Frame-Options: DENY\n"), 0600)).To(Succeed())
+
+		build = redirects.Build(scribe.NewEmitter(buffer))
+		buildCtx = packit.BuildContext{
+			BuildpackInfo: packit.Info{
+				ID:      "some-org/redirects",
+				Name:    "Some Buildpack",
+				Version: "some-version",
+			},
+			WorkingDir: workingDir,
+			Plan: packit.BuildpackPlan{
+				Entries: []packit.BuildpackPlanEntry{
+					{
+						Name:     "web-server-selection",
+						Metadata: map[string]interface{}{"server": "nginx"},
+					},
+				},
+			},
+		}
+	})
+
+	context("when the group serves the app with nginx", func() {
+		it("writes the nginx fragments", func() {
+			result, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(packit.BuildResult{}))
+
+			server, err := os.ReadFile(filepath.Join(workingDir, "web-servers", "nginx", "server", "10-redirects.conf"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(server)).To(ContainSubstring(`if ($uri ~ "^/old$") { return 301 /new$is_args$args; }`))
+
+			headers, err := os.ReadFile(filepath.Join(workingDir, "web-servers", "nginx", "headers", "10-redirects.conf"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(headers)).To(ContainSubstring("add_header X-Frame-Options $netlify_header_0 always;"))
+
+			http, err := os.ReadFile(filepath.Join(workingDir, "web-servers", "nginx", "http", "10-redirects.conf"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(http)).To(ContainSubstring(`"~^/(.*)$" "DENY";`))
+
+			Expect(filepath.Join(workingDir, "web-servers", "httpd")).NotTo(BeAnExistingFile())
+
+			Expect(buffer.String()).To(ContainSubstring("Some Buildpack some-version"))
+			Expect(buffer.String()).To(ContainSubstring("Translating _redirects and _headers in build for nginx"))
+			Expect(buffer.String()).To(ContainSubstring("1 redirect rules, 1 header rules"))
+			Expect(buffer.String()).To(ContainSubstring("Wrote web-servers/nginx/server/10-redirects.conf"))
+			Expect(buffer.String()).NotTo(ContainSubstring("Included the fragments"))
+		})
+
+		context("and the app provides an nginx.conf", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "nginx.conf"), []byte("http {\n  server {\n  }\n}\n"), 0600)).To(Succeed())
+			})
+
+			it("includes the fragments in it", func() {
+				_, err := build(buildCtx)
+				Expect(err).NotTo(HaveOccurred())
+
+				config, err := os.ReadFile(filepath.Join(workingDir, "nginx.conf"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(config)).To(ContainSubstring("include web-servers/nginx/http/*.conf;"))
+				Expect(string(config)).To(ContainSubstring("include web-servers/nginx/server/*.conf;"))
+				Expect(string(config)).To(ContainSubstring("include web-servers/nginx/headers/*.conf;"))
+
+				Expect(buffer.String()).To(ContainSubstring("Included the fragments in nginx.conf"))
+			})
+		})
+	})
+
+	context("when the group serves the app with httpd", func() {
+		it.Before(func() {
+			buildCtx.Plan.Entries[0].Metadata["server"] = "httpd"
+		})
+
+		it("writes the httpd fragment", func() {
+			_, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(filepath.Join(workingDir, "web-servers", "httpd", "10-redirects.conf")).To(BeARegularFile())
+		})
+
+		context("and the app provides an httpd.conf", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "httpd.conf"), []byte(`DocumentRoot "${APP_ROOT}/build"`), 0600)).To(Succeed())
+			})
+
+			it("writes the httpd fragment and includes it in httpd.conf", func() {
+				_, err := build(buildCtx)
+				Expect(err).NotTo(HaveOccurred())
+
+				content, err := os.ReadFile(filepath.Join(workingDir, "web-servers", "httpd", "10-redirects.conf"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(HavePrefix("# Generated by the some-org/redirects buildpack"))
+				Expect(string(content)).To(ContainSubstring(`RewriteRule "^/old$" "/new" [R=301,L]`))
+
+				config, err := os.ReadFile(filepath.Join(workingDir, "httpd.conf"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(config)).To(HaveSuffix("\nIncludeOptional \"${APP_ROOT}/web-servers/httpd/*.conf\"\n"))
+
+				Expect(filepath.Join(workingDir, "web-servers", "nginx")).NotTo(BeAnExistingFile())
+
+				Expect(buffer.String()).To(ContainSubstring("Translating _redirects and _headers in build for httpd"))
+				Expect(buffer.String()).To(ContainSubstring("Wrote web-servers/httpd/10-redirects.conf"))
+				Expect(buffer.String()).To(ContainSubstring("Included the fragments in httpd.conf"))
+				Expect(buffer.String()).NotTo(ContainSubstring("Warning"))
+			})
+		})
+
+		context("and the app does not provide httpd.conf", func() {
+			it("warns", func() {
+				_, err := build(buildCtx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(buffer.String()).To(ContainSubstring("Warning: the rules only apply to an app that provides httpd.conf"))
+			})
+		})
+	})
+
+	context("when the rule files are only in the application source", func() {
+		it.Before(func() {
+			Expect(os.RemoveAll(filepath.Join(workingDir, "build"))).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(workingDir, "_redirects"), []byte("/old /new\n"), 0600)).To(Succeed())
+		})
+
+		it("reads them from there", func() {
+			_, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buffer.String()).To(ContainSubstring("Translating _redirects and _headers in . for nginx"))
+			Expect(buffer.String()).To(ContainSubstring("1 redirect rules, 0 header rules"))
+		})
+	})
+
+	context("when the rule files are in public/ of a static site", func() {
+		it.Before(func() {
+			Expect(os.RemoveAll(filepath.Join(workingDir, "build"))).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(workingDir, "public"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(workingDir, "public", "_redirects"), []byte("/old /new\n"), 0600)).To(Succeed())
+		})
+
+		it("reads them from there", func() {
+			_, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(filepath.Join(workingDir, "web-servers", "nginx", "server", "10-redirects.conf")).To(BeARegularFile())
+			Expect(buffer.String()).To(ContainSubstring("Translating _redirects and _headers in public for nginx"))
+			Expect(buffer.String()).To(ContainSubstring("1 redirect rules, 0 header rules"))
+		})
+	})
+
+	context("when the rule files are in static/ of a static site", func() {
+		it.Before(func() {
+			Expect(os.RemoveAll(filepath.Join(workingDir, "build"))).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(workingDir, "static"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(workingDir, "static", "_headers"), []byte("/*\n  X-Frame-Options: DENY\n"), 0600)).To(Succeed())
+		})
+
+		it("reads them from there", func() {
+			_, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(filepath.Join(workingDir, "web-servers", "nginx", "headers", "10-redirects.conf")).To(BeARegularFile())
+			Expect(buffer.String()).To(ContainSubstring("Translating _redirects and _headers in static for nginx"))
+			Expect(buffer.String()).To(ContainSubstring("0 redirect rules, 1 header rules"))
+		})
+	})
+
+	context("when the rule files are in the build output and in public/", func() {
+		it.Before(func() {
+			Expect(os.MkdirAll(filepath.Join(workingDir, "public"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(workingDir, "public", "_redirects"), []byte("/a /b\n/c /d\n"), 0600)).To(Succeed())
+		})
+
+		it("reads them from the build output", func() {
+			_, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buffer.String()).To(ContainSubstring("Translating _redirects and _headers in build for nginx"))
+			Expect(buffer.String()).To(ContainSubstring("1 redirect rules, 1 header rules"))
+		})
+	})
+
+	context("when the rule files did not reach the build output", func() {
+		it.Before(func() {
+			Expect(os.Remove(filepath.Join(workingDir, "build", "_redirects"))).To(Succeed())
+			Expect(os.Remove(filepath.Join(workingDir, "build", "_headers"))).To(Succeed())
+		})
+
+		it("does nothing", func() {
+			_, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(filepath.Join(workingDir, "web-servers")).NotTo(BeAnExistingFile())
+			Expect(buffer.String()).To(ContainSubstring("No _redirects or _headers file was found in build, ., public, static"))
+		})
+	})
+
+	context("failure cases", func() {
+		context("when _redirects is invalid", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "build", "_redirects"), []byte("/old\n"), 0600)).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError("_redirects line 1: expected a path and a destination"))
+			})
+		})
+
+		context("when the selected server is not supported", func() {
+			it.Before(func() {
+				buildCtx.Plan.Entries[0].Metadata["server"] = "caddy"
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError("_redirects and _headers are not supported for caddy"))
+			})
+		})
+	})
+}
@@ -0,0 +1,20 @@
+package redirects
+
+// RedirectsFile and HeadersFile are the Netlify-style rule files read from
+// the document root, or from the application source when there is no build
+// output.
+const (
+	RedirectsFile = "_redirects"
+	HeadersFile   = "_headers"
+)
+
+// Fragment is the name of the configuration fragments holding the translated
+// rules. It sorts first so that redirects apply before the fragments of other
+// features.
+const Fragment = "10-redirects"
+
+// SourceDirectories are searched for rule files during detection, before the
+// frontend has been built, and in the same order during the build when the
+// build output has none. Toolchains copy public/ (Create React App, Vite)
+// and static/ (Hugo) into their build output.
+var SourceDirectories = []string{".", "public", "static"}
@@ -0,0 +1,31 @@
+package redirects
+
+import (
+	"path/filepath"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/fs"
+	webserverselector "github.com/paketo-buildpacks/web-servers/buildpacks/web-server-selector"
+)
+
+// Detect passes when the application source contains a _redirects or
+// _headers file in any of SourceDirectories. Its plan receives the server
+// chosen by the web server selector.
+func Detect() packit.DetectFunc {
+	return func(context packit.DetectContext) (packit.DetectResult, error) {
+		for _, dir := range SourceDirectories {
+			for _, file := range []string{RedirectsFile, HeadersFile} {
+				exists, err := fs.Exists(filepath.Join(context.WorkingDir, dir, file))
+				if err != nil {
+					return packit.DetectResult{}, err
+				}
+
+				if exists {
+					return packit.DetectResult{Plan: webserverselector.SelectionPlan()}, nil
+				}
+			}
+		}
+
+		return packit.DetectResult{}, packit.Fail.WithMessage("no %s or %s file was found", RedirectsFile, HeadersFile)
+	}
+}
@@ -0,0 +1,56 @@
+package redirects_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/web-servers/buildpacks/redirects"
+	webserverselector "github.com/paketo-buildpacks/web-servers/buildpacks/web-server-selector"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDetect(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+		detect     packit.DetectFunc
+	)
+
+	it.Before(func() {
+		workingDir = t.TempDir()
+		detect = redirects.Detect()
+	})
+
+	for _, path := range []string{"_redirects", "_headers", "public/_redirects", "static/_headers"} {
+		path := path
+
+		context("when "+path+" exists", func() {
+			it.Before(func() {
+				Expect(os.MkdirAll(filepath.Dir(filepath.Join(workingDir, path)), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, path), nil, 0600)).To(Succeed())
+			})
+
+			it("passes", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(Equal(packit.DetectResult{Plan: webserverselector.SelectionPlan()}))
+			})
+		})
+	}
+
+	context("when there are no rule files", func() {
+		it("fails", func() {
+			_, err := detect(packit.DetectContext{
+				WorkingDir: workingDir,
+			})
+			Expect(err).To(MatchError(packit.Fail.WithMessage("no _redirects or _headers file was found")))
+		})
+	})
+}
@@ -0,0 +1,49 @@
+package redirects
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Httpd renders the rules as a fragment for the main server context of
+// httpd.conf. Rewrites match the full path, and rewrites with status 200 only
+// apply when no file or directory matches, as on Netlify.
+func Httpd(generator string, redirects []Redirect, headers []HeaderRule) ([]byte, error) {
+	buffer := bytes.NewBuffer(nil)
+
+	fmt.Fprintf(buffer, "# Generated by the %s buildpack from %s and %s.\n", generator, RedirectsFile, HeadersFile)
+	buffer.WriteString("<IfModule !mod_rewrite.c>\n  LoadModule rewrite_module modules/mod_rewrite.so\n</IfModule>\n")
+	buffer.WriteString("<IfModule !mod_headers.c>\n  LoadModule headers_module modules/mod_headers.so\n</IfModule>\n")
+	buffer.WriteString("RewriteEngine On\n")
+	fmt.Fprintf(buffer, "RewriteRule \"/(%s|%s)$\" - [R=404,L]\n", RedirectsFile, HeadersFile)
+
+	for _, rule := range redirects {
+		fmt.Fprintf(buffer, "\n# %s line %d: %s -> %s %d\n", RedirectsFile, rule.Line, rule.From.Source, rule.To, rule.Status)
+
+		switch rule.Status {
+		case 200:
+			buffer.WriteString("RewriteCond %{DOCUMENT_ROOT}%{REQUEST_URI} !-f\n")
+			buffer.WriteString("RewriteCond %{DOCUMENT_ROOT}%{REQUEST_URI} !-d\n")
+			fmt.Fprintf(buffer, "RewriteRule \"%s\" \"%s\" [PT,L]\n", rule.From.Regexp(), rule.Target())
+		case 404:
+			fmt.Fprintf(buffer, "ErrorDocument 404 %s\n", rule.To)
+		default:
+			fmt.Fprintf(buffer, "RewriteRule \"%s\" \"%s\" [R=%d,L]\n", rule.From.Regexp(), rule.Target(), rule.Status)
+		}
+	}
+
+	// Later Header directives replace earlier ones, so rules are written in
+	// reverse for the first matching rule to win, as it does for nginx.
+	for _, rule := range slices.Backward(headers) {
+		fmt.Fprintf(buffer, "\n# %s line %d: %s\n", HeadersFile, rule.Line, rule.Path.Source)
+		fmt.Fprintf(buffer, "<If \"%%{REQUEST_URI} =~ m#%s#\">\n", rule.Path.Regexp())
+		for _, header := range rule.Headers {
+			fmt.Fprintf(buffer, "  Header always set %s \"%s\"\n", header.Name, strings.ReplaceAll(header.Value, "%", "%%"))
+		}
+		buffer.WriteString("</If>\n")
+	}
+
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,69 @@
+package redirects_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/paketo-buildpacks/web-servers/buildpacks/redirects"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testHttpd(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	it("renders a server context fragment", func() {
+		rules, err := redirects.ParseRedirects(strings.NewReader(`/old /new
+/docs/* https://docs.example.com/:splat 302
+/app/* /index.html 200
+/* /404.html 404`))
+		Expect(err).NotTo(HaveOccurred())
+
+		headers, err := redirects.ParseHeaders(strings.NewReader(`/*
+  X-Frame-Options: DENY
+/static/*
+  Cache-Control: public, max-age=31536000
+  X-Progress: 100%`))
+		Expect(err).NotTo(HaveOccurred())
+
+		content, err := redirects.Httpd("some-org/redirects", rules, headers)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(string(content)).To(Equal(`# Generated by the some-org/redirects buildpack from _redirects and _headers.
+<IfModule !mod_rewrite.c>
+  LoadModule rewrite_module modules/mod_rewrite.so
+</IfModule>
+<IfModule !mod_headers.c>
+  LoadModule headers_module modules/mod_headers.so
+</IfModule>
+RewriteEngine On
+RewriteRule "/(_redirects|_headers)$" - [R=404,L]
+
+# _redirects line 1: /old -> /new 301
+RewriteRule "^/old$" "/new" [R=301,L]
+
+# _redirects line 2: /docs/* -> https://docs.example.com/:splat 302
+RewriteRule "^/docs(?:/(.*))?$" "https://docs.example.com/$1" [R=302,L]
+
+# _redirects line 3: /app/* -> /index.html 200
+RewriteCond %{DOCUMENT_ROOT}%{REQUEST_URI} !-f
+RewriteCond %{DOCUMENT_ROOT}%{REQUEST_URI} !-d
+RewriteRule "^/app(?:/(.*))?$" "/index.html" [PT,L]
+
+# _redirects line 4: /* -> /404.html 404
+ErrorDocument 404 /404.html
+
+# _headers line 3: /static/*
+<If "%{REQUEST_URI} =~ m#^/static(?:/(.*))?$#">
+  Header always set Cache-Control "public, max-age=31536000"
+  Header always set X-Progress "100%%"
+</If>
+
+# _headers line 1: /*
+<If "%{REQUEST_URI} =~ m#^/(.*)$#">
+  Header always set X-Frame-Options "DENY"
+</If>
+`))
+	})
+}
@@ -0,0 +1,18 @@
+package redirects_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitRedirects(t *testing.T) {
+	suite := spec.New("redirects", spec.Report(report.Terminal{}), spec.Sequential())
+	suite("Detect", testDetect)
+	suite("Build", testBuild)
+	suite("Rules", testRules)
+	suite("Nginx", testNginx)
+	suite("Httpd", testHttpd)
+	suite.Run(t)
+}
@@ -0,0 +1,76 @@
+package redirects
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/paketo-buildpacks/web-servers/internal/serverconf"
+)
+
+// Nginx renders the rules as a fragment for each NGINX context. Redirects are
+// server-level rewrite directives, so they run before location matching and
+// in file order. Headers are looked up by path through a map per header name,
+// because add_header cannot be conditional; an empty value leaves the header
+// unset.
+func Nginx(generator string, redirects []Redirect, headers []HeaderRule) (map[serverconf.Context][]byte, error) {
+	http := bytes.NewBuffer(nil)
+	server := bytes.NewBuffer(nil)
+	headerFragment := bytes.NewBuffer(nil)
+
+	fmt.Fprintf(http, "# Generated by the %s buildpack from %s.\n", generator, HeadersFile)
+	fmt.Fprintf(server, "# Generated by the %s buildpack from %s.\n", generator, RedirectsFile)
+	fmt.Fprintf(server, "if ($uri ~ \"/(%s|%s)$\") { return 404; }\n", RedirectsFile, HeadersFile)
+	fmt.Fprintf(headerFragment, "# Generated by the %s buildpack from %s.\n", generator, HeadersFile)
+
+	for _, rule := range redirects {
+		fmt.Fprintf(server, "\n# %s line %d: %s -> %s %d\n", RedirectsFile, rule.Line, rule.From.Source, rule.To, rule.Status)
+
+		switch rule.Status {
+		case 200:
+			fmt.Fprintf(server, "if (!-e $request_filename) { rewrite \"%s\" %s last; }\n", rule.From.Regexp(), rule.Target())
+		case 404:
+			fmt.Fprintf(server, "error_page 404 %s;\n", rule.To)
+		default:
+			target := rule.Target()
+			if !strings.Contains(target, "?") {
+				target += "$is_args$args"
+			}
+
+			fmt.Fprintf(server, "if ($uri ~ \"%s\") { return %d %s; }\n", rule.From.Regexp(), rule.Status, target)
+		}
+	}
+
+	var names []string
+	values := map[string][]string{}
+	for _, rule := range headers {
+		for _, header := range rule.Headers {
+			if strings.Contains(header.Value, "$") {
+				return nil, fmt.Errorf("%s line %d: header values containing $ are not supported for nginx", HeadersFile, rule.Line)
+			}
+
+			key := strings.ToLower(header.Name)
+			if _, ok := values[key]; !ok {
+				names = append(names, header.Name)
+			}
+
+			values[key] = append(values[key], fmt.Sprintf("  \"~%s\" \"%s\";", rule.Path.Regexp(), header.Value))
+		}
+	}
+
+	for i, name := range names {
+		variable := fmt.Sprintf("$netlify_header_%d", i)
+
+		fmt.Fprintf(http, "\nmap $uri %s {\n", variable)
+		http.WriteString(strings.Join(values[strings.ToLower(name)], "\n"))
+		http.WriteString("\n}\n")
+
+		fmt.Fprintf(headerFragment, "add_header %s %s always;\n", name, variable)
+	}
+
+	return map[serverconf.Context][]byte{
+		serverconf.NginxHTTP:    http.Bytes(),
+		serverconf.NginxServer:  server.Bytes(),
+		serverconf.NginxHeaders: headerFragment.Bytes(),
+	}, nil
+}
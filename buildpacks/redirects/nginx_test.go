@@ -0,0 +1,79 @@
+package redirects_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/paketo-buildpacks/web-servers/buildpacks/redirects"
+	"github.com/paketo-buildpacks/web-servers/internal/serverconf"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testNginx(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	it("renders a fragment for each context", func() {
+		rules, err := redirects.ParseRedirects(strings.NewReader(`/old /new
+/blog/:slug /posts/:slug?from=blog 308
+/app/* /index.html 200
+/* /404.html 404`))
+		Expect(err).NotTo(HaveOccurred())
+
+		headers, err := redirects.ParseHeaders(strings.NewReader(`/*
+  X-Frame-Options: DENY
+/static/*
+  X-Frame-Options: SAMEORIGIN
+  Cache-Control: public, max-age=31536000`))
+		Expect(err).NotTo(HaveOccurred())
+
+		fragments, err := redirects.Nginx("some-org/redirects", rules, headers)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(string(fragments[serverconf.NginxHTTP])).To(Equal(`# Generated by the some-org/redirects buildpack from _headers.
+
+map $uri $netlify_header_0 {
+  "~^/(.*)$" "DENY";
+  "~^/static(?:/(.*))?$" "SAMEORIGIN";
+}
+
+map $uri $netlify_header_1 {
+  "~^/static(?:/(.*))?$" "public, max-age=31536000";
+}
+`))
+
+		Expect(string(fragments[serverconf.NginxServer])).To(Equal(`# Generated by the some-org/redirects buildpack from _redirects.
+if ($uri ~ "/(_redirects|_headers)$") { return 404; }
+
+# _redirects line 1: /old -> /new 301
+if ($uri ~ "^/old$") { return 301 /new$is_args$args; }
+
+# _redirects line 2: /blog/:slug -> /posts/:slug?from=blog 308
+if ($uri ~ "^/blog/([^/]+)$") { return 308 /posts/$1?from=blog; }
+
+# _redirects line 3: /app/* -> /index.html 200
+if (!-e $request_filename) { rewrite "^/app(?:/(.*))?$" /index.html last; }
+
+# _redirects line 4: /* -> /404.html 404
+error_page 404 /404.html;
+`))
+
+		Expect(string(fragments[serverconf.NginxHeaders])).To(Equal(`# Generated by the some-org/redirects buildpack from _headers.
+add_header X-Frame-Options $netlify_header_0 always;
+add_header Cache-Control $netlify_header_1 always;
+`))
+	})
+
+	context("failure cases", func() {
+		context("when a header value contains $", func() {
+			it("returns an error", func() {
+				headers, err := redirects.ParseHeaders(strings.NewReader("/*\n  X-Price: $5"))
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = redirects.Nginx("some-org/redirects", nil, headers)
+				Expect(err).To(MatchError("_headers line 1: header values containing $ are not supported for nginx"))
+			})
+		})
+	})
+}
@@ -0,0 +1,274 @@
+package redirects
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Redirect is a single rule from a _redirects file.
+type Redirect struct {
+	// Line is the line number the rule was read from.
+	Line int
+
+	From Pattern
+	To   string
+
+	// Status is 301, 302, 303, 307 or 308 for redirects, 200 for rewrites and
+	// 404 for a custom not-found page.
+	Status int
+
+	// Force is set by a trailing ! on the status. Redirects always apply and
+	// rewrites never shadow existing files, so it is accepted but has no
+	// further effect.
+	Force bool
+}
+
+// External reports whether the rule targets another site.
+func (r Redirect) External() bool {
+	return strings.HasPrefix(r.To, "http://") || strings.HasPrefix(r.To, "https://")
+}
+
+// Target returns the destination with each :placeholder and :splat replaced
+// by the $N backreference to its capture group, which both NGINX and HTTPD
+// understand.
+func (r Redirect) Target() string {
+	return placeholder.ReplaceAllStringFunc(r.To, func(match string) string {
+		index := slices.Index(r.From.Names, match[1:])
+		if index < 0 {
+			return match
+		}
+
+		return "$" + strconv.Itoa(index+1)
+	})
+}
+
+// HeaderRule is a path pattern and the headers set on responses to it, read
+// from a _headers file.
+type HeaderRule struct {
+	// Line is the line number of the path.
+	Line int
+
+	Path    Pattern
+	Headers []Header
+}
+
+// Header is a single response header.
+type Header struct {
+	Name  string
+	Value string
+}
+
+// Pattern is a path that may contain :placeholder segments and end in a *
+// splat, as accepted by both file formats.
+type Pattern struct {
+	// Source is the path as written.
+	Source string
+
+	// Names lists the placeholder names in capture group order; a splat is
+	// named "splat".
+	Names []string
+
+	segments []string
+	splat    bool
+}
+
+var (
+	placeholder = regexp.MustCompile(`:[A-Za-z_][A-Za-z0-9_]*`)
+	headerName  = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+)
+
+// unsafe lists the characters that are rejected in paths and destinations
+// because they would end a quoted or unquoted argument in server configuration.
+const unsafe = `"';{}\`
+
+// ParsePattern validates a path pattern.
+func ParsePattern(source string) (Pattern, error) {
+	if !strings.HasPrefix(source, "/") {
+		return Pattern{}, fmt.Errorf("%q must be a path starting with /", source)
+	}
+
+	if strings.ContainsAny(source, unsafe+"#") {
+		return Pattern{}, fmt.Errorf("%q contains characters that cannot be translated", source)
+	}
+
+	pattern := Pattern{Source: source}
+	segments := strings.Split(source[1:], "/")
+	for i, segment := range segments {
+		switch {
+		case segment == "*" && i == len(segments)-1:
+			pattern.splat = true
+			pattern.Names = append(pattern.Names, "splat")
+			continue
+
+		case strings.Contains(segment, "*"):
+			return Pattern{}, fmt.Errorf("%q may only contain * as its last segment", source)
+
+		case strings.HasPrefix(segment, ":"):
+			pattern.Names = append(pattern.Names, segment[1:])
+		}
+
+		pattern.segments = append(pattern.segments, segment)
+	}
+
+	return pattern, nil
+}
+
+// Regexp returns an anchored regular expression matching the pattern.
+func (p Pattern) Regexp() string {
+	var parts []string
+	for _, segment := range p.segments {
+		if strings.HasPrefix(segment, ":") {
+			parts = append(parts, "([^/]+)")
+			continue
+		}
+
+		parts = append(parts, regexp.QuoteMeta(segment))
+	}
+
+	expression := "/" + strings.Join(parts, "/")
+	if p.splat {
+		// A splat also matches the path without it, so /blog/* matches /blog
+		if len(parts) == 0 {
+			expression = "/(.*)"
+		} else {
+			expression += "(?:/(.*))?"
+		}
+	}
+
+	return "^" + expression + "$"
+}
+
+// ParseRedirects reads the rules in a _redirects file. Features that cannot be
+// translated faithfully, such as query parameter matching, conditions and
+// proxying to other sites, are rejected rather than ignored.
+func ParseRedirects(reader io.Reader) ([]Redirect, error) {
+	var rules []Redirect
+
+	scanner := bufio.NewScanner(reader)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		rule, err := parseRedirect(line, fields)
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", RedirectsFile, line, err)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", RedirectsFile, err)
+	}
+
+	return rules, nil
+}
+
+func parseRedirect(line int, fields []string) (Redirect, error) {
+	if len(fields) < 2 {
+		return Redirect{}, fmt.Errorf("expected a path and a destination")
+	}
+
+	// Query parameters to match are listed between the path and the
+	// destination, as in "/store id=:id /products/:id"
+	if strings.Contains(fields[1], "=") && !strings.HasPrefix(fields[1], "/") && !strings.Contains(fields[1], "://") {
+		return Redirect{}, fmt.Errorf("query parameter matching is not supported")
+	}
+
+	from, err := ParsePattern(fields[0])
+	if err != nil {
+		return Redirect{}, err
+	}
+
+	rule := Redirect{Line: line, From: from, To: fields[1], Status: 301}
+	if !strings.HasPrefix(rule.To, "/") && !rule.External() {
+		return Redirect{}, fmt.Errorf("destination %q must be a path or an http(s) URL", rule.To)
+	}
+
+	if strings.ContainsAny(rule.To, unsafe) {
+		return Redirect{}, fmt.Errorf("destination %q contains characters that cannot be translated", rule.To)
+	}
+
+	rest := fields[2:]
+	if len(rest) > 0 {
+		status, force := strings.CutSuffix(rest[0], "!")
+		if code, err := strconv.Atoi(status); err == nil {
+			rule.Status, rule.Force = code, force
+			rest = rest[1:]
+		}
+	}
+
+	if len(rest) > 0 {
+		return Redirect{}, fmt.Errorf("conditions are not supported: %s", strings.Join(rest, " "))
+	}
+
+	switch rule.Status {
+	case 301, 302, 303, 307, 308:
+	case 200:
+		if rule.External() {
+			return Redirect{}, fmt.Errorf("proxying to %s is not supported", rule.To)
+		}
+	case 404:
+		if from.Source != "/*" {
+			return Redirect{}, fmt.Errorf("404 rules are only supported for /*")
+		}
+	default:
+		return Redirect{}, fmt.Errorf("status %d is not supported", rule.Status)
+	}
+
+	return rule, nil
+}
+
+// ParseHeaders reads the rules in a _headers file: unindented path patterns,
+// each followed by indented "Name: value" lines.
+func ParseHeaders(reader io.Reader) ([]HeaderRule, error) {
+	var rules []HeaderRule
+
+	scanner := bufio.NewScanner(reader)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		trimmed := strings.TrimSpace(text)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if trimmed == text {
+			path, err := ParsePattern(trimmed)
+			if err != nil {
+				return nil, fmt.Errorf("%s line %d: %w", HeadersFile, line, err)
+			}
+
+			rules = append(rules, HeaderRule{Line: line, Path: path})
+			continue
+		}
+
+		if len(rules) == 0 {
+			return nil, fmt.Errorf("%s line %d: header is not preceded by a path", HeadersFile, line)
+		}
+
+		name, value, ok := strings.Cut(trimmed, ":")
+		if !ok || !headerName.MatchString(strings.TrimSpace(name)) {
+			return nil, fmt.Errorf("%s line %d: expected \"Name: value\"", HeadersFile, line)
+		}
+
+		if strings.ContainsAny(value, `"\`) {
+			return nil, fmt.Errorf("%s line %d: header values cannot contain quotes or backslashes", HeadersFile, line)
+		}
+
+		rule := &rules[len(rules)-1]
+		rule.Headers = append(rule.Headers, Header{Name: strings.TrimSpace(name), Value: strings.TrimSpace(value)})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", HeadersFile, err)
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,148 @@
+package redirects_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/paketo-buildpacks/web-servers/buildpacks/redirects"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testRules(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("ParsePattern", func() {
+		it("translates placeholders and splats into capture groups", func() {
+			pattern, err := redirects.ParsePattern("/blog/:year/:slug/*")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pattern.Names).To(Equal([]string{"year", "slug", "splat"}))
+			Expect(pattern.Regexp()).To(Equal(`^/blog/([^/]+)/([^/]+)(?:/(.*))?$`))
+		})
+
+		it("escapes literal segments", func() {
+			pattern, err := redirects.ParsePattern("/old.html")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pattern.Regexp()).To(Equal(`^/old\.html$`))
+		})
+
+		it("matches everything for /*", func() {
+			pattern, err := redirects.ParsePattern("/*")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pattern.Regexp()).To(Equal(`^/(.*)$`))
+		})
+
+		context("failure cases", func() {
+			it("rejects paths that do not start with /", func() {
+				_, err := redirects.ParsePattern("https://example.com/*")
+				Expect(err).To(MatchError(`"https://example.com/*" must be a path starting with /`))
+			})
+
+			it("rejects splats before the last segment", func() {
+				_, err := redirects.ParsePattern("/blog/*/comments")
+				Expect(err).To(MatchError(`"/blog/*/comments" may only contain * as its last segment`))
+			})
+
+			it("rejects characters that would break the configuration", func() {
+				_, err := redirects.ParsePattern(`/a";b`)
+				Expect(err).To(MatchError(`"/a\";b" contains characters that cannot be translated`))
+			})
+		})
+	})
+
+	context("ParseRedirects", func() {
+		it("parses rules, skipping blank lines and comments", func() {
+			rules, err := redirects.ParseRedirects(strings.NewReader(`
+# Moved pages
+/old        /new
+/blog/:slug /posts/:slug  302
+/docs/*     https://docs.example.com/:splat 301!
+/app/*      /index.html   200
+/*          /404.html     404
+`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rules).To(HaveLen(5))
+
+			Expect(rules[0].Line).To(Equal(3))
+			Expect(rules[0].Status).To(Equal(301))
+			Expect(rules[0].Target()).To(Equal("/new"))
+
+			Expect(rules[1].Status).To(Equal(302))
+			Expect(rules[1].Target()).To(Equal("/posts/$1"))
+
+			Expect(rules[2].Force).To(BeTrue())
+			Expect(rules[2].External()).To(BeTrue())
+			Expect(rules[2].Target()).To(Equal("https://docs.example.com/$1"))
+
+			Expect(rules[3].Status).To(Equal(200))
+			Expect(rules[4].Status).To(Equal(404))
+		})
+
+		context("failure cases", func() {
+			for _, example := range []struct {
+				rule  string
+				error string
+			}{
+				{rule: "/old", error: "_redirects line 1: expected a path and a destination"},
+				{rule: "/store id=:id /blog/:id 301", error: "_redirects line 1: query parameter matching is not supported"},
+				{rule: "/old new", error: `_redirects line 1: destination "new" must be a path or an http(s) URL`},
+				{rule: "/ /fr 302 Language=fr", error: "_redirects line 1: conditions are not supported: Language=fr"},
+				{rule: "/api/* https://api.example.com/:splat 200", error: "_redirects line 1: proxying to https://api.example.com/:splat is not supported"},
+				{rule: "/docs/* /docs/404.html 404", error: "_redirects line 1: 404 rules are only supported for /*"},
+				{rule: "/old /new 410", error: "_redirects line 1: status 410 is not supported"},
+			} {
+				example := example
+
+				it("rejects "+example.rule, func() {
+					_, err := redirects.ParseRedirects(strings.NewReader(example.rule))
+					Expect(err).To(MatchError(example.error))
+				})
+			}
+		})
+	})
+
+	context("ParseHeaders", func() {
+		it("parses paths and their indented headers", func() {
+			rules, err := redirects.ParseHeaders(strings.NewReader(`
+# Security
+/*
+  X-Frame-Options: DENY
+  Content-Security-Policy: default-src 'self'
+
+/static/*
+  Cache-Control: public, max-age=31536000
+`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rules).To(HaveLen(2))
+
+			Expect(rules[0].Line).To(Equal(3))
+			Expect(rules[0].Path.Source).To(Equal("/*"))
+			Expect(rules[0].Headers).To(Equal([]redirects.Header{
+				{Name: "X-Frame-Options", Value: "DENY"},
+				{Name: "Content-Security-Policy", Value: "default-src 'self'"},
+			}))
+
+			Expect(rules[1].Headers).To(Equal([]redirects.Header{
+				{Name: "Cache-Control", Value: "public, max-age=31536000"},
+			}))
+		})
+
+		context("failure cases", func() {
+			it("rejects headers without a path", func() {
+				_, err := redirects.ParseHeaders(strings.NewReader("  X-Frame-Options: DENY"))
+				Expect(err).To(MatchError("_headers line 1: header is not preceded by a path"))
+			})
+
+			it("rejects malformed headers", func() {
+				_, err := redirects.ParseHeaders(strings.NewReader("/*\n  X-Frame-Options DENY"))
+				Expect(err).To(MatchError(`_headers line 2: expected "Name: value"`))
+			})
+
+			it("rejects values with quotes", func() {
+				_, err := redirects.ParseHeaders(strings.NewReader("/*\n  X-Note: say \"hi\""))
+				Expect(err).To(MatchError("_headers line 2: header values cannot contain quotes or backslashes"))
+			})
+		})
+	})
+}
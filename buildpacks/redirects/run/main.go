@@ -0,0 +1,18 @@
+package main
+
+import (
+	"os"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	"github.com/paketo-buildpacks/web-servers/buildpacks/redirects"
+)
+
+func main() {
+	logger := scribe.NewEmitter(os.Stdout).WithLevel(os.Getenv("BP_LOG_LEVEL"))
+
+	packit.Run(
+		redirects.Detect(),
+		redirects.Build(logger),
+	)
+}
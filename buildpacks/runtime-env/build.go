@@ -0,0 +1,140 @@
+package runtimeenv
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/fs"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	webserverselector "github.com/paketo-buildpacks/web-servers/buildpacks/web-server-selector"
+	"github.com/paketo-buildpacks/web-servers/internal/docroot"
+	"github.com/paketo-buildpacks/web-servers/internal/serverconf"
+)
+
+// Build contributes a launch layer whose exec.d helper writes the allowed
+// runtime environment variables into RuntimeDir each time the image starts,
+// before the web server process is launched, and configures the web server of
+// the order group to serve the file from there. A placeholder in the document
+// root keeps the path a file for rules that only apply when none matches.
+func Build(logger scribe.Emitter) packit.BuildFunc {
+	return func(context packit.BuildContext) (packit.BuildResult, error) {
+		logger.Title("%s %s", context.BuildpackInfo.Name, context.BuildpackInfo.Version)
+
+		allowlist, err := ParseAllowlist(os.Getenv("BP_RUNTIME_ENV_ALLOWLIST"))
+		if err != nil {
+			return packit.BuildResult{}, fmt.Errorf("failed to parse BP_RUNTIME_ENV_ALLOWLIST: %w", err)
+		}
+
+		file := DefaultFile
+		if value, ok := os.LookupEnv("BP_RUNTIME_ENV_FILE"); ok && value != "" {
+			file = value
+		}
+
+		if !filepath.IsLocal(file) {
+			return packit.BuildResult{}, fmt.Errorf("BP_RUNTIME_ENV_FILE must be a path inside the document root, got %q", file)
+		}
+		file = filepath.ToSlash(filepath.Clean(file))
+
+		root, reason, err := docroot.Find(context.WorkingDir)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		server, _, err := webserverselector.Selected(context.Plan)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		var fragments map[serverconf.Context][]byte
+		switch server.Name {
+		case "nginx":
+			fragments = map[serverconf.Context][]byte{serverconf.NginxServer: Nginx(context.BuildpackInfo.ID, file)}
+		case "httpd":
+			fragments = map[serverconf.Context][]byte{serverconf.Httpd: Httpd(context.BuildpackInfo.ID, file)}
+		case "caddy":
+			fragments = map[serverconf.Context][]byte{serverconf.Caddy: Caddy(context.BuildpackInfo.ID, file)}
+		default:
+			return packit.BuildResult{}, fmt.Errorf("BP_RUNTIME_ENV_ALLOWLIST is not supported for %s", server.Name)
+		}
+
+		logger.Process("Configuring runtime environment injection")
+		logger.Subprocess("Writing %s at launch, served at /%s (%s)", path.Join(RuntimeDir, file), file, reason)
+		logger.Subprocess("Exposing %s", strings.Join(allowlist, ", "))
+
+		placeholder, err := Render(nil, allowlist, file)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		err = os.MkdirAll(filepath.Dir(filepath.Join(context.WorkingDir, root, file)), os.ModePerm)
+		if err != nil {
+			return packit.BuildResult{}, fmt.Errorf("failed to write placeholder: %w", err)
+		}
+
+		err = os.WriteFile(filepath.Join(context.WorkingDir, root, file), placeholder, 0644)
+		if err != nil {
+			return packit.BuildResult{}, fmt.Errorf("failed to write placeholder: %w", err)
+		}
+
+		logger.Subprocess("Wrote the placeholder %s", path.Join(root, file))
+
+		for _, fragmentContext := range slices.Sorted(maps.Keys(fragments)) {
+			err = serverconf.Write(context.WorkingDir, fragmentContext, Fragment, fragments[fragmentContext])
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			logger.Subprocess("Wrote %s/%s/%s.conf", serverconf.Dir, fragmentContext, Fragment)
+		}
+
+		// A generated nginx.conf includes the fragments itself; one provided by
+		// the app has the includes added
+		injected, err := serverconf.Inject(context.WorkingDir, server.Name)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		if injected {
+			logger.Subprocess("Included the fragments in %s", server.ConfigFile)
+		}
+
+		// The configuration the httpd buildpack generates has no includes
+		if server.Name == "httpd" {
+			exists, err := fs.Exists(filepath.Join(context.WorkingDir, server.ConfigFile))
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			if !exists {
+				logger.Subprocess("Warning: /%s is only served for an app that provides %s", file, server.ConfigFile)
+			}
+		}
+
+		layer, err := context.Layers.Get(LayerName)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		layer, err = layer.Reset()
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		layer.Launch = true
+		layer.ExecD = []string{filepath.Join(context.CNBPath, "bin", HelperName)}
+		layer.LaunchEnv.Default(AllowlistEnv, strings.Join(allowlist, ","))
+		layer.LaunchEnv.Override(PathEnv, path.Join(RuntimeDir, file))
+
+		logger.Break()
+
+		return packit.BuildResult{
+			Layers: []packit.Layer{layer},
+		}, nil
+	}
+}
@@ -0,0 +1,237 @@
+package runtimeenv_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	runtimeenv "github.com/paketo-buildpacks/web-servers/buildpacks/runtime-env"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testBuild(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+		layersDir  string
+		buffer     *bytes.Buffer
+		build      packit.BuildFunc
+		buildCtx   packit.BuildContext
+	)
+
+	it.Before(func() {
+		workingDir = t.TempDir()
+		layersDir = t.TempDir()
+		buffer = bytes.NewBuffer(nil)
+
+		Expect(os.MkdirAll(filepath.Join(workingDir, "dist"), os.ModePerm)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(workingDir, "dist", "index.html"), nil, 0600)).To(Succeed())
+
+		t.Setenv("BP_RUNTIME_ENV_ALLOWLIST", "PUBLIC_*, API_URL")
+
+		build = runtimeenv.Build(scribe.NewEmitter(buffer))
+		buildCtx = packit.BuildContext{
+			BuildpackInfo: packit.Info{
+				ID:      "some-org/runtime-env",
+				Name:    "Some Buildpack",
+				Version: "some-version",
+			},
+			CNBPath:    "/cnb/buildpacks/some-buildpack",
+			WorkingDir: workingDir,
+			Layers:     packit.Layers{Path: layersDir},
+			Plan: packit.BuildpackPlan{
+				Entries: []packit.BuildpackPlanEntry{
+					{
+						Name:     "web-server-selection",
+						Metadata: map[string]interface{}{"server": "nginx"},
+					},
+				},
+			},
+		}
+	})
+
+	it("contributes a launch layer with the exec.d helper", func() {
+		result, err := build(buildCtx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Layers).To(HaveLen(1))
+		layer := result.Layers[0]
+		Expect(layer.Name).To(Equal("runtime-env"))
+		Expect(layer.Path).To(Equal(filepath.Join(layersDir, "runtime-env")))
+		Expect(layer.Launch).To(BeTrue())
+		Expect(layer.Build).To(BeFalse())
+		Expect(layer.Cache).To(BeFalse())
+		Expect(layer.ExecD).To(Equal([]string{"/cnb/buildpacks/some-buildpack/bin/env-js"}))
+		Expect(layer.LaunchEnv).To(Equal(packit.Environment{
+			"RUNTIME_ENV_ALLOWLIST.default": "PUBLIC_*,API_URL",
+			"RUNTIME_ENV_PATH.override":     "/tmp/runtime-env/env.js",
+		}))
+
+		Expect(buffer.String()).To(ContainSubstring("Some Buildpack some-version"))
+		Expect(buffer.String()).To(ContainSubstring("Writing /tmp/runtime-env/env.js at launch, served at /env.js (dist/index.html was found)"))
+		Expect(buffer.String()).To(ContainSubstring("Exposing PUBLIC_*, API_URL"))
+	})
+
+	it("writes a placeholder to the document root and serves the file from the runtime directory", func() {
+		_, err := build(buildCtx)
+		Expect(err).NotTo(HaveOccurred())
+
+		placeholder, err := os.ReadFile(filepath.Join(workingDir, "dist", "env.js"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(placeholder)).To(Equal("window.__ENV__ = {};\n"))
+
+		fragment, err := os.ReadFile(filepath.Join(workingDir, "web-servers", "nginx", "server", "15-runtime-env.conf"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(fragment)).To(ContainSubstring("location = /env.js {\n  alias /tmp/runtime-env/env.js;\n"))
+
+		Expect(buffer.String()).To(ContainSubstring("Wrote the placeholder dist/env.js"))
+		Expect(buffer.String()).To(ContainSubstring("Wrote web-servers/nginx/server/15-runtime-env.conf"))
+	})
+
+	context("when the app provides an nginx.conf", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(workingDir, "nginx.conf"), []byte("http {\n  server {\n    root dist;\n  }\n}\n"), 0600)).To(Succeed())
+		})
+
+		it("includes the fragments in it", func() {
+			_, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+
+			config, err := os.ReadFile(filepath.Join(workingDir, "nginx.conf"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(config)).To(ContainSubstring("include web-servers/nginx/server/*.conf;"))
+
+			Expect(buffer.String()).To(ContainSubstring("Included the fragments in nginx.conf"))
+		})
+	})
+
+	context("when the group serves the app with httpd", func() {
+		it.Before(func() {
+			buildCtx.Plan.Entries[0].Metadata["server"] = "httpd"
+			Expect(os.WriteFile(filepath.Join(workingDir, "httpd.conf"), []byte(`DocumentRoot "${APP_ROOT}/dist"`), 0600)).To(Succeed())
+		})
+
+		it("writes the httpd fragment and includes it", func() {
+			_, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+
+			fragment, err := os.ReadFile(filepath.Join(workingDir, "web-servers", "httpd", "15-runtime-env.conf"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(fragment)).To(ContainSubstring(`Alias "/env.js" "/tmp/runtime-env/env.js"`))
+
+			config, err := os.ReadFile(filepath.Join(workingDir, "httpd.conf"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(config)).To(ContainSubstring(`IncludeOptional "${APP_ROOT}/web-servers/httpd/*.conf"`))
+
+			Expect(buffer.String()).NotTo(ContainSubstring("Warning"))
+		})
+
+		context("and the app does not provide httpd.conf", func() {
+			it.Before(func() {
+				Expect(os.Remove(filepath.Join(workingDir, "httpd.conf"))).To(Succeed())
+			})
+
+			it("warns", func() {
+				_, err := build(buildCtx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(buffer.String()).To(ContainSubstring("Warning: /env.js is only served for an app that provides httpd.conf"))
+			})
+		})
+	})
+
+	context("when the group serves the app with caddy", func() {
+		it.Before(func() {
+			buildCtx.Plan.Entries[0].Metadata["server"] = "caddy"
+		})
+
+		it("writes the caddy fragment", func() {
+			_, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+
+			fragment, err := os.ReadFile(filepath.Join(workingDir, "web-servers", "caddy", "15-runtime-env.conf"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(fragment)).To(ContainSubstring("handle /env.js {\n\troot * /tmp/runtime-env\n"))
+		})
+	})
+
+	context("when BP_RUNTIME_ENV_FILE is set", func() {
+		it.Before(func() {
+			t.Setenv("BP_RUNTIME_ENV_FILE", "config/env.json")
+		})
+
+		it("writes that file instead", func() {
+			result, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Layers[0].LaunchEnv).To(HaveKeyWithValue("RUNTIME_ENV_PATH.override", "/tmp/runtime-env/config/env.json"))
+
+			placeholder, err := os.ReadFile(filepath.Join(workingDir, "dist", "config", "env.json"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(placeholder)).To(Equal("{}\n"))
+		})
+	})
+
+	context("when BP_WEB_SERVER_ROOT is set", func() {
+		it.Before(func() {
+			Expect(os.MkdirAll(filepath.Join(workingDir, "public"), os.ModePerm)).To(Succeed())
+			t.Setenv("BP_WEB_SERVER_ROOT", "public")
+		})
+
+		it("writes the placeholder to that directory", func() {
+			_, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(filepath.Join(workingDir, "public", "env.js")).To(BeARegularFile())
+		})
+	})
+
+	context("failure cases", func() {
+		context("when BP_RUNTIME_ENV_ALLOWLIST allows every variable", func() {
+			it.Before(func() {
+				t.Setenv("BP_RUNTIME_ENV_ALLOWLIST", "*")
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(ContainSubstring("failed to parse BP_RUNTIME_ENV_ALLOWLIST: runtime environment allowlist must not contain a bare *")))
+			})
+		})
+
+		context("when BP_RUNTIME_ENV_FILE leaves the document root", func() {
+			it.Before(func() {
+				t.Setenv("BP_RUNTIME_ENV_FILE", "../env.js")
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(`BP_RUNTIME_ENV_FILE must be a path inside the document root, got "../env.js"`))
+			})
+		})
+
+		context("when no web server was selected", func() {
+			it.Before(func() {
+				buildCtx.Plan.Entries = nil
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(ContainSubstring("web-server-selection")))
+			})
+		})
+
+		context("when there is no build output", func() {
+			it.Before(func() {
+				Expect(os.RemoveAll(filepath.Join(workingDir, "dist"))).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(ContainSubstring("failed to find build output")))
+			})
+		})
+	})
+}
@@ -0,0 +1,31 @@
+package runtimeenv
+
+// LayerName is the launch layer that holds the exec.d helper.
+const LayerName = "runtime-env"
+
+// HelperName is the exec.d executable, built from env-js/, that renders the
+// environment file before the web server starts.
+const HelperName = "env-js"
+
+// DefaultFile is the path below the document root that the environment
+// file is served at when BP_RUNTIME_ENV_FILE is not set.
+const DefaultFile = "env.js"
+
+// RuntimeDir is where the helper writes the environment file at launch. The
+// application source may be read-only in the container, or owned by another
+// user, while the web servers already need a writable /tmp.
+const RuntimeDir = "/tmp/runtime-env"
+
+// Fragment is the name of the configuration fragments that serve the
+// environment file from RuntimeDir.
+const Fragment = "15-runtime-env"
+
+// Global is the browser global that env.js assigns the variables to.
+const Global = "window.__ENV__"
+
+// The helper reads its configuration from these launch environment variables,
+// which Build sets from the build-time configuration.
+const (
+	AllowlistEnv = "RUNTIME_ENV_ALLOWLIST"
+	PathEnv      = "RUNTIME_ENV_PATH"
+)
@@ -0,0 +1,27 @@
+package runtimeenv
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	webserverselector "github.com/paketo-buildpacks/web-servers/buildpacks/web-server-selector"
+)
+
+// Detect passes when BP_RUNTIME_ENV_ALLOWLIST names at least one variable to
+// expose, so that no environment reaches the browser unless asked for. Its
+// plan receives the server chosen by the web server selector.
+func Detect() packit.DetectFunc {
+	return func(context packit.DetectContext) (packit.DetectResult, error) {
+		allowlist, err := ParseAllowlist(os.Getenv("BP_RUNTIME_ENV_ALLOWLIST"))
+		if err != nil {
+			return packit.DetectResult{}, fmt.Errorf("failed to parse BP_RUNTIME_ENV_ALLOWLIST: %w", err)
+		}
+
+		if len(allowlist) == 0 {
+			return packit.DetectResult{}, packit.Fail.WithMessage("BP_RUNTIME_ENV_ALLOWLIST is not set")
+		}
+
+		return packit.DetectResult{Plan: webserverselector.SelectionPlan()}, nil
+	}
+}
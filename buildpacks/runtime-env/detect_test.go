@@ -0,0 +1,72 @@
+package runtimeenv_test
+
+import (
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	runtimeenv "github.com/paketo-buildpacks/web-servers/buildpacks/runtime-env"
+	webserverselector "github.com/paketo-buildpacks/web-servers/buildpacks/web-server-selector"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDetect(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		detect packit.DetectFunc
+	)
+
+	it.Before(func() {
+		detect = runtimeenv.Detect()
+	})
+
+	context("when BP_RUNTIME_ENV_ALLOWLIST is set", func() {
+		it.Before(func() {
+			t.Setenv("BP_RUNTIME_ENV_ALLOWLIST", "PUBLIC_*")
+		})
+
+		it("passes", func() {
+			result, err := detect(packit.DetectContext{
+				WorkingDir: t.TempDir(),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(packit.DetectResult{Plan: webserverselector.SelectionPlan()}))
+		})
+	})
+
+	context("when BP_RUNTIME_ENV_ALLOWLIST is not set", func() {
+		it("fails", func() {
+			_, err := detect(packit.DetectContext{
+				WorkingDir: t.TempDir(),
+			})
+			Expect(err).To(MatchError(packit.Fail.WithMessage("BP_RUNTIME_ENV_ALLOWLIST is not set")))
+		})
+	})
+
+	context("when BP_RUNTIME_ENV_ALLOWLIST is empty", func() {
+		it.Before(func() {
+			t.Setenv("BP_RUNTIME_ENV_ALLOWLIST", " , ")
+		})
+
+		it("fails", func() {
+			_, err := detect(packit.DetectContext{
+				WorkingDir: t.TempDir(),
+			})
+			Expect(err).To(MatchError(packit.Fail.WithMessage("BP_RUNTIME_ENV_ALLOWLIST is not set")))
+		})
+	})
+	context("when BP_RUNTIME_ENV_ALLOWLIST is a bare *", func() {
+		it.Before(func() {
+			t.Setenv("BP_RUNTIME_ENV_ALLOWLIST", "*")
+		})
+
+		it("returns an error", func() {
+			_, err := detect(packit.DetectContext{
+				WorkingDir: t.TempDir(),
+			})
+			Expect(err).To(MatchError(ContainSubstring("failed to parse BP_RUNTIME_ENV_ALLOWLIST: runtime environment allowlist must not contain a bare *")))
+		})
+	})
+}
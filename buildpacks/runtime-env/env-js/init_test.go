@@ -0,0 +1,14 @@
+package main_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitEnvJS(t *testing.T) {
+	suite := spec.New("env-js", spec.Report(report.Terminal{}), spec.Sequential())
+	suite("EnvJS", testEnvJS)
+	suite.Run(t)
+}
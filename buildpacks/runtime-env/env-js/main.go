@@ -0,0 +1,42 @@
+// Command env-js is the exec.d helper contributed by the runtime-env
+// buildpack. The launcher runs it before the web server starts, and it writes
+// the environment file to RUNTIME_ENV_PATH, below the runtime-env RuntimeDir,
+// where the web server serves it from.
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	runtimeenv "github.com/paketo-buildpacks/web-servers/buildpacks/runtime-env"
+)
+
+func main() {
+	path := os.Getenv(runtimeenv.PathEnv)
+	if path == "" {
+		fmt.Fprintf(os.Stderr, "%s is not set\n", runtimeenv.PathEnv)
+		os.Exit(1)
+	}
+
+	allowlist, err := runtimeenv.ParseAllowlist(os.Getenv(runtimeenv.AllowlistEnv))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to parse %s: %s\n", runtimeenv.AllowlistEnv, err)
+		os.Exit(1)
+	}
+
+	content, err := runtimeenv.Render(os.Environ(), allowlist, path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	if err == nil {
+		err = os.WriteFile(path, content, 0644)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write runtime environment: %s\n%s must be writable by the user the container runs as\n", err, filepath.Dir(path))
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,75 @@
+package main_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega/gexec"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testEnvJS(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		helper string
+		dir    string
+	)
+
+	it.Before(func() {
+		var err error
+		helper, err = gexec.Build("github.com/paketo-buildpacks/web-servers/buildpacks/runtime-env/env-js")
+		Expect(err).NotTo(HaveOccurred())
+
+		dir = t.TempDir()
+	})
+
+	it.After(func() {
+		gexec.CleanupBuildArtifacts()
+	})
+
+	run := func(path string) *gexec.Session {
+		cmd := exec.Command(helper)
+		cmd.Env = []string{
+			"RUNTIME_ENV_ALLOWLIST=PUBLIC_*",
+			"RUNTIME_ENV_PATH=" + path,
+			"PUBLIC_API_URL=https://api.example.com",
+			"SECRET_TOKEN=some-secret",
+		}
+
+		session, err := gexec.Start(cmd, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		return session
+	}
+
+	it("writes the allowed variables to the path", func() {
+		session := run(filepath.Join(dir, "runtime-env", "env.js"))
+		Eventually(session).Should(gexec.Exit(0))
+
+		content, err := os.ReadFile(filepath.Join(dir, "runtime-env", "env.js"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal(`window.__ENV__ = {"PUBLIC_API_URL":"https://api.example.com"};` + "\n"))
+	})
+
+	context("failure cases", func() {
+		context("when the directory cannot be written", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(dir, "runtime-env"), nil, 0600)).To(Succeed())
+			})
+
+			it("explains what must be writable", func() {
+				session := run(filepath.Join(dir, "runtime-env", "env.js"))
+				Eventually(session).Should(gexec.Exit(1))
+
+				Expect(string(session.Err.Contents())).To(ContainSubstring("failed to write runtime environment: "))
+				Expect(string(session.Err.Contents())).To(ContainSubstring(filepath.Join(dir, "runtime-env") + " must be writable by the user the container runs as\n"))
+			})
+		})
+	})
+}
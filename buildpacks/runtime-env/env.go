@@ -0,0 +1,75 @@
+package runtimeenv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Allowlist is a set of environment variable names that may be exposed to
+// the browser. Entries ending in * match any variable with that prefix.
+type Allowlist []string
+
+// ParseAllowlist splits a comma- or whitespace-separated list of names and
+// prefixes, such as "PUBLIC_*, API_URL". A bare * is rejected, as it would
+// publish every variable of the container, including secrets and binding
+// paths, in the document root.
+func ParseAllowlist(value string) (Allowlist, error) {
+	allowlist := Allowlist(strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	}))
+
+	for _, entry := range allowlist {
+		if entry == "*" {
+			return nil, errors.New("runtime environment allowlist must not contain a bare *: use a prefix such as PUBLIC_*")
+		}
+	}
+
+	return allowlist, nil
+}
+
+// Allows reports whether the variable may be exposed.
+func (a Allowlist) Allows(name string) bool {
+	for _, entry := range a {
+		if prefix, ok := strings.CutSuffix(entry, "*"); ok {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+
+			continue
+		}
+
+		if name == entry {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Render returns the contents of the environment file at path for the
+// allowed variables in environ, given in the "NAME=value" form of
+// os.Environ. Files ending in .json hold a JSON object; any other file is a
+// script that assigns the object to window.__ENV__.
+func Render(environ []string, allowlist Allowlist, path string) ([]byte, error) {
+	values := map[string]string{}
+	for _, variable := range environ {
+		name, value, _ := strings.Cut(variable, "=")
+		if allowlist.Allows(name) {
+			values[name] = value
+		}
+	}
+
+	content, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode runtime environment: %w", err)
+	}
+
+	if filepath.Ext(path) == ".json" {
+		return append(content, '\n'), nil
+	}
+
+	return []byte(fmt.Sprintf("%s = %s;\n", Global, content)), nil
+}
@@ -0,0 +1,70 @@
+package runtimeenv_test
+
+import (
+	"testing"
+
+	runtimeenv "github.com/paketo-buildpacks/web-servers/buildpacks/runtime-env"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testEnv(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("ParseAllowlist", func() {
+		it("splits on commas and whitespace", func() {
+			allowlist, err := runtimeenv.ParseAllowlist("PUBLIC_*, API_URL\tVITE_*")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allowlist).To(Equal(runtimeenv.Allowlist{"PUBLIC_*", "API_URL", "VITE_*"}))
+		})
+
+		context("when an entry is a bare *", func() {
+			it("returns an error", func() {
+				_, err := runtimeenv.ParseAllowlist("PUBLIC_*, *")
+				Expect(err).To(MatchError("runtime environment allowlist must not contain a bare *: use a prefix such as PUBLIC_*"))
+			})
+		})
+	})
+
+	context("Allows", func() {
+		it("matches exact names and prefixes", func() {
+			allowlist := runtimeenv.Allowlist{"PUBLIC_*", "API_URL"}
+
+			Expect(allowlist.Allows("PUBLIC_TITLE")).To(BeTrue())
+			Expect(allowlist.Allows("API_URL")).To(BeTrue())
+			Expect(allowlist.Allows("API_URL_SECRET")).To(BeFalse())
+			Expect(allowlist.Allows("SECRET_KEY")).To(BeFalse())
+		})
+	})
+
+	context("Render", func() {
+		var environ []string
+
+		it.Before(func() {
+			environ = []string{
+				"PUBLIC_TITLE=Hello </script>",
+				"PUBLIC_API=https://api.example.com?a=b",
+				"SECRET_KEY=hunter2",
+			}
+		})
+
+		it("renders a script assigning the allowed variables, escaped for HTML", func() {
+			content, err := runtimeenv.Render(environ, runtimeenv.Allowlist{"PUBLIC_*"}, "/workspace/build/env.js")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal(`window.__ENV__ = {"PUBLIC_API":"https://api.example.com?a=b","PUBLIC_TITLE":"Hello \u003c/script\u003e"};` + "\n"))
+		})
+
+		it("renders JSON for .json files", func() {
+			content, err := runtimeenv.Render(environ, runtimeenv.Allowlist{"PUBLIC_API"}, "/workspace/build/env.json")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(MatchJSON(`{"PUBLIC_API":"https://api.example.com?a=b"}`))
+		})
+
+		it("renders an empty object when nothing is allowed", func() {
+			content, err := runtimeenv.Render(environ, nil, "env.js")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("window.__ENV__ = {};\n"))
+		})
+	})
+}
@@ -0,0 +1,17 @@
+package runtimeenv_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitRuntimeEnv(t *testing.T) {
+	suite := spec.New("runtime-env", spec.Report(report.Terminal{}), spec.Sequential())
+	suite("Detect", testDetect)
+	suite("Build", testBuild)
+	suite("Env", testEnv)
+	suite("Serve", testServe)
+	suite.Run(t)
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"os"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	runtimeenv "github.com/paketo-buildpacks/web-servers/buildpacks/runtime-env"
+)
+
+func main() {
+	logger := scribe.NewEmitter(os.Stdout).WithLevel(os.Getenv("BP_LOG_LEVEL"))
+
+	packit.Run(
+		runtimeenv.Detect(),
+		runtimeenv.Build(logger),
+	)
+}
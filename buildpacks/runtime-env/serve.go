@@ -0,0 +1,46 @@
+package runtimeenv
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+)
+
+// Nginx renders the fragment for the server block of nginx.conf that serves
+// the file at /file from RuntimeDir. The location sets its own headers, so it
+// includes the header fragments of the server.
+func Nginx(generator, file string) []byte {
+	buffer := bytes.NewBuffer(nil)
+	fmt.Fprintf(buffer, "# Generated by the %s buildpack for BP_RUNTIME_ENV_ALLOWLIST.\n", generator)
+	fmt.Fprintf(buffer, "location = /%s {\n", file)
+	fmt.Fprintf(buffer, "  alias %s;\n", path.Join(RuntimeDir, file))
+	buffer.WriteString("  include web-servers/nginx/headers/*.conf;\n")
+	buffer.WriteString("}\n")
+
+	return buffer.Bytes()
+}
+
+// Httpd renders the fragment for httpd.conf that serves the file at /file
+// from RuntimeDir.
+func Httpd(generator, file string) []byte {
+	buffer := bytes.NewBuffer(nil)
+	fmt.Fprintf(buffer, "# Generated by the %s buildpack for BP_RUNTIME_ENV_ALLOWLIST.\n", generator)
+	buffer.WriteString("<IfModule !mod_alias.c>\n  LoadModule alias_module modules/mod_alias.so\n</IfModule>\n")
+	fmt.Fprintf(buffer, "Alias \"/%s\" \"%s\"\n", file, path.Join(RuntimeDir, file))
+	fmt.Fprintf(buffer, "<Directory \"%s\">\n  Require all granted\n</Directory>\n", RuntimeDir)
+
+	return buffer.Bytes()
+}
+
+// Caddy renders the fragment for the site block of the Caddyfile that serves
+// the file at /file from RuntimeDir.
+func Caddy(generator, file string) []byte {
+	buffer := bytes.NewBuffer(nil)
+	fmt.Fprintf(buffer, "# Generated by the %s buildpack for BP_RUNTIME_ENV_ALLOWLIST.\n", generator)
+	fmt.Fprintf(buffer, "handle /%s {\n", file)
+	fmt.Fprintf(buffer, "\troot * %s\n", RuntimeDir)
+	buffer.WriteString("\tfile_server\n")
+	buffer.WriteString("}\n")
+
+	return buffer.Bytes()
+}
@@ -0,0 +1,45 @@
+package runtimeenv_test
+
+import (
+	"testing"
+
+	runtimeenv "github.com/paketo-buildpacks/web-servers/buildpacks/runtime-env"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testServe(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("Nginx", func() {
+		it("serves the file from the runtime directory with the server's headers", func() {
+			Expect(string(runtimeenv.Nginx("some-buildpack", "config/env.json"))).To(Equal(`# Generated by the some-buildpack buildpack for BP_RUNTIME_ENV_ALLOWLIST.
+location = /config/env.json {
+  alias /tmp/runtime-env/config/env.json;
+  include web-servers/nginx/headers/*.conf;
+}
+`))
+		})
+	})
+
+	context("Httpd", func() {
+		it("aliases the file to the runtime directory", func() {
+			Expect(string(runtimeenv.Httpd("some-buildpack", "env.js"))).To(Equal(`# Generated by the some-buildpack buildpack for BP_RUNTIME_ENV_ALLOWLIST.
+<IfModule !mod_alias.c>
+  LoadModule alias_module modules/mod_alias.so
+</IfModule>
+Alias "/env.js" "/tmp/runtime-env/env.js"
+<Directory "/tmp/runtime-env">
+  Require all granted
+</Directory>
+`))
+		})
+	})
+
+	context("Caddy", func() {
+		it("serves the file from the runtime directory", func() {
+			Expect(string(runtimeenv.Caddy("some-buildpack", "env.js"))).To(Equal("# Generated by the some-buildpack buildpack for BP_RUNTIME_ENV_ALLOWLIST.\nhandle /env.js {\n\troot * /tmp/runtime-env\n\tfile_server\n}\n"))
+		})
+	})
+}
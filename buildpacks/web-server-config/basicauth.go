@@ -0,0 +1,61 @@
+package webserverconfig
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/paketo-buildpacks/packit/v2/servicebindings"
+	"github.com/paketo-buildpacks/web-servers/internal/serverconf"
+)
+
+// Realm is shown by browsers when they prompt for credentials.
+const Realm = "Restricted"
+
+// BasicAuth renders the fragments that require the users in the htpasswd
+// binding for every request except those for the open paths. Without a
+// binding it returns no fragments, so the same image can run with and without
+// authentication. NGINX locations for open paths turn auth_basic off
+// themselves, so they are only listed for HTTPD.
+func BasicAuth(server string, bindings []servicebindings.Binding, open []string) (map[serverconf.Context][]byte, error) {
+	name, paths, err := boundEntries(bindings, HtpasswdBindingType, HtpasswdBindingType)
+	if err != nil || name == "" {
+		return nil, err
+	}
+
+	path := paths[0]
+
+	buffer := bytes.NewBuffer(nil)
+	fmt.Fprintf(buffer, "# Generated at launch from the %s service binding.\n", name)
+
+	switch server {
+	case "nginx":
+		fmt.Fprintf(buffer, "auth_basic \"%s\";\n", Realm)
+		fmt.Fprintf(buffer, "auth_basic_user_file \"%s\";\n", path)
+
+		return map[serverconf.Context][]byte{serverconf.NginxServer: buffer.Bytes()}, nil
+
+	case "httpd":
+		for _, module := range []string{"auth_basic", "authn_core", "authn_file", "authz_core", "authz_user"} {
+			fmt.Fprintf(buffer, "<IfModule !mod_%s.c>\n  LoadModule %s_module modules/mod_%s.so\n</IfModule>\n", module, module, module)
+		}
+
+		// Location sections are merged after Directory sections, so this
+		// replaces the "Require all granted" of the document root
+		buffer.WriteString("<Location \"/\">\n")
+		buffer.WriteString("  AuthType Basic\n")
+		fmt.Fprintf(buffer, "  AuthName \"%s\"\n", Realm)
+		fmt.Fprintf(buffer, "  AuthUserFile \"%s\"\n", path)
+		buffer.WriteString("  Require valid-user\n")
+		buffer.WriteString("</Location>\n")
+
+		// Later sections take precedence
+		for _, path := range open {
+			fmt.Fprintf(buffer, "<Location \"%s\">\n  Require all granted\n</Location>\n", path)
+		}
+
+		return map[serverconf.Context][]byte{serverconf.Httpd: buffer.Bytes()}, nil
+
+	default:
+		return nil, fmt.Errorf("basic authentication is not supported for %s", server)
+	}
+}
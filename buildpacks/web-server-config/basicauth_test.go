@@ -0,0 +1,105 @@
+package webserverconfig_test
+
+import (
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/v2/servicebindings"
+	webserverconfig "github.com/paketo-buildpacks/web-servers/buildpacks/web-server-config"
+	"github.com/paketo-buildpacks/web-servers/internal/serverconf"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testBasicAuth(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		bindings []servicebindings.Binding
+	)
+
+	it.Before(func() {
+		bindings = []servicebindings.Binding{
+			{
+				Name:    "some-binding",
+				Path:    "/bindings/some-binding",
+				Type:    "htpasswd",
+				Entries: map[string]*servicebindings.Entry{"htpasswd": servicebindings.NewEntry("/bindings/some-binding/htpasswd")},
+			},
+		}
+	})
+
+	it("renders the nginx fragment", func() {
+		fragments, err := webserverconfig.BasicAuth("nginx", bindings, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fragments).To(HaveLen(1))
+		Expect(string(fragments[serverconf.NginxServer])).To(Equal(`# Generated at launch from the some-binding service binding.
+auth_basic "Restricted";
+auth_basic_user_file "/bindings/some-binding/htpasswd";
+`))
+	})
+
+	it("renders the httpd fragment", func() {
+		fragments, err := webserverconfig.BasicAuth("httpd", bindings, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fragments).To(HaveLen(1))
+		Expect(string(fragments[serverconf.Httpd])).To(ContainSubstring(`<IfModule !mod_auth_basic.c>
+  LoadModule auth_basic_module modules/mod_auth_basic.so
+</IfModule>`))
+		Expect(string(fragments[serverconf.Httpd])).To(HaveSuffix(`<Location "/">
+  AuthType Basic
+  AuthName "Restricted"
+  AuthUserFile "/bindings/some-binding/htpasswd"
+  Require valid-user
+</Location>
+`))
+	})
+
+	context("when a path is left open", func() {
+		it("grants every request for it with HTTPD", func() {
+			fragments, err := webserverconfig.BasicAuth("httpd", bindings, []string{"/healthz"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(fragments[serverconf.Httpd])).To(HaveSuffix(`  Require valid-user
+</Location>
+<Location "/healthz">
+  Require all granted
+</Location>
+`))
+		})
+	})
+
+	context("when there is no binding", func() {
+		it("renders nothing", func() {
+			fragments, err := webserverconfig.BasicAuth("nginx", nil, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fragments).To(BeEmpty())
+		})
+	})
+
+	context("failure cases", func() {
+		context("when there is more than one binding", func() {
+			it("returns an error", func() {
+				_, err := webserverconfig.BasicAuth("nginx", append(bindings, bindings[0]), nil)
+				Expect(err).To(MatchError("found 2 service bindings of type htpasswd, expected at most one"))
+			})
+		})
+
+		context("when the binding has no htpasswd entry", func() {
+			it.Before(func() {
+				bindings[0].Entries = map[string]*servicebindings.Entry{}
+			})
+
+			it("returns an error", func() {
+				_, err := webserverconfig.BasicAuth("nginx", bindings, nil)
+				Expect(err).To(MatchError("service binding some-binding has no htpasswd entry"))
+			})
+		})
+
+		context("when the server is not supported", func() {
+			it("returns an error", func() {
+				_, err := webserverconfig.BasicAuth("caddy", bindings, nil)
+				Expect(err).To(MatchError("basic authentication is not supported for caddy"))
+			})
+		})
+	})
+}
@@ -0,0 +1,40 @@
+package webserverconfig
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/paketo-buildpacks/packit/v2/servicebindings"
+)
+
+// boundEntries returns the name of the only binding in bindings, which are
+// of bindingType, and the paths of its entries. It returns no name when
+// nothing is bound.
+func boundEntries(bindings []servicebindings.Binding, bindingType string, entries ...string) (string, []string, error) {
+	if len(bindings) == 0 {
+		return "", nil, nil
+	}
+
+	if len(bindings) > 1 {
+		return "", nil, fmt.Errorf("found %d service bindings of type %s, expected at most one", len(bindings), bindingType)
+	}
+
+	binding := bindings[0]
+
+	var paths []string
+	for _, entry := range entries {
+		if _, ok := binding.Entries[entry]; !ok {
+			return "", nil, fmt.Errorf("service binding %s has no %s entry", binding.Name, entry)
+		}
+
+		path := filepath.Join(binding.Path, entry)
+		if strings.ContainsAny(path, `"\`) {
+			return "", nil, fmt.Errorf("service binding path %q cannot be quoted", path)
+		}
+
+		paths = append(paths, path)
+	}
+
+	return binding.Name, paths, nil
+}
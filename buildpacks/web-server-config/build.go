@@ -0,0 +1,385 @@
+package webserverconfig
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	webserverselector "github.com/paketo-buildpacks/web-servers/buildpacks/web-server-selector"
+	"github.com/paketo-buildpacks/web-servers/internal/docroot"
+	"github.com/paketo-buildpacks/web-servers/internal/serverconf"
+)
+
+// Build applies each enabled feature to the application source and writes
+// the configuration fragments that enable it in the web server of the order
+// group. It runs after the frontend has been built and before the server's
+// buildpack reads its configuration.
+func Build(logger scribe.Emitter) packit.BuildFunc {
+	return func(context packit.BuildContext) (packit.BuildResult, error) {
+		logger.Title("%s %s", context.BuildpackInfo.Name, context.BuildpackInfo.Version)
+
+		server, _, err := webserverselector.Selected(context.Plan)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		var result packit.BuildResult
+
+		health, err := flag(HealthCheckEnv)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		if health {
+			label, err := healthCheck(context, logger, server)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			result.Launch.Labels = map[string]string{HealthCheckLabelName: label}
+		}
+
+		routes, err := Routes(context.WorkingDir, os.Getenv(ProxyEnv))
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		if len(routes) > 0 {
+			err = proxy(context, logger, server, routes)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+		}
+
+		encodings, err := ParseEncodings(os.Getenv(PrecompressEnv))
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		if len(encodings) > 0 {
+			err = precompress(context, logger, server, encodings)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+		}
+
+		profile, headers, err := SecurityHeaders(os.Environ())
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		if len(headers) > 0 {
+			err = securityHeaders(context, logger, server, profile, headers)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+		}
+
+		basicAuth, err := flag(BasicAuthEnv)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		tls, err := flag(TLSEnv)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		metrics, err := flag(MetricsEnv)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		if metrics {
+			err = statusPage(context, logger, server)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			result.Launch.Processes = append(result.Launch.Processes, packit.Process{
+				Type:    MetricsProcess,
+				Command: filepath.Join(context.CNBPath, "bin", MetricsProcess),
+				Args:    []string{server.Name},
+				Direct:  true,
+			})
+
+			logger.Subprocess("Added the %s process, which runs the web process and serves Prometheus metrics next to it on $%s (default %s)", MetricsProcess, MetricsPortEnv, DefaultMetricsPort)
+		}
+
+		// Features configured from service bindings are applied at launch by
+		// the exec.d helper
+		if basicAuth || tls {
+			if basicAuth && server.Name != "nginx" && server.Name != "httpd" {
+				return packit.BuildResult{}, unsupported(BasicAuthEnv, server)
+			}
+
+			layer, err := launchLayer(context, server)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			if basicAuth {
+				layer.LaunchEnv.Override(BasicAuthFlagEnv, "true")
+				if health {
+					layer.LaunchEnv.Override(HealthCheckPathEnv, HealthCheckPath)
+				}
+
+				logger.Process("Enabling basic authentication at launch")
+				logger.Subprocess("Requires the users in a service binding of type %s, when one is bound", HtpasswdBindingType)
+			}
+
+			if tls {
+				layer.LaunchEnv.Override(TLSFlagEnv, "true")
+
+				logger.Process("Enabling TLS at launch")
+				logger.Subprocess("Serves HTTPS on $PORT with the certificate in a service binding of type %s, when one is bound", TLSBindingType)
+			}
+
+			result.Layers = append(result.Layers, layer)
+		}
+
+		// A generated nginx.conf includes the fragments itself; one provided by
+		// the app has the includes added
+		injected, err := serverconf.Inject(context.WorkingDir, server.Name)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		if injected {
+			logger.Process("Included the configuration fragments in %s", server.ConfigFile)
+		}
+
+		logger.Break()
+
+		return result, nil
+	}
+}
+
+// flag parses a boolean setting, which is false when unset.
+func flag(name string) (bool, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return false, nil
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+
+	return enabled, nil
+}
+
+func unsupported(setting string, server webserverselector.Server) error {
+	return fmt.Errorf("%s is not supported for %s", setting, server.Name)
+}
+
+// launchLayer returns the launch layer whose exec.d helper writes the
+// fragments of launch-time features.
+func launchLayer(context packit.BuildContext, server webserverselector.Server) (packit.Layer, error) {
+	layer, err := context.Layers.Get(LayerName)
+	if err != nil {
+		return packit.Layer{}, err
+	}
+
+	layer, err = layer.Reset()
+	if err != nil {
+		return packit.Layer{}, err
+	}
+
+	layer.Launch = true
+	layer.ExecD = []string{filepath.Join(context.CNBPath, "bin", HelperName)}
+	layer.LaunchEnv.Override(ServerEnv, server.Name)
+	layer.LaunchEnv.Override(AppRootEnv, context.WorkingDir)
+
+	return layer, nil
+}
+
+func healthCheck(context packit.BuildContext, logger scribe.Emitter, server webserverselector.Server) (string, error) {
+	logger.Process("Answering health checks at %s", HealthCheckPath)
+
+	var fragments map[serverconf.Context][]byte
+	switch server.Name {
+	case "nginx":
+		fragments = NginxHealthCheck(context.BuildpackInfo.ID)
+	case "httpd":
+		err := os.MkdirAll(filepath.Join(context.WorkingDir, serverconf.Dir), os.ModePerm)
+		if err != nil {
+			return "", err
+		}
+
+		err = os.WriteFile(filepath.Join(context.WorkingDir, serverconf.Dir, HealthCheckFile), []byte("ok"), 0644)
+		if err != nil {
+			return "", fmt.Errorf("failed to write %s: %w", HealthCheckFile, err)
+		}
+
+		fragments = map[serverconf.Context][]byte{
+			serverconf.Httpd: HttpdHealthCheck(context.BuildpackInfo.ID),
+		}
+	case "caddy":
+		fragments = map[serverconf.Context][]byte{
+			serverconf.Caddy: CaddyHealthCheck(context.BuildpackInfo.ID),
+		}
+	}
+
+	err := writeFragments(context.WorkingDir, logger, HealthCheckFragment, fragments)
+	if err != nil {
+		return "", err
+	}
+
+	label, err := HealthCheckLabel()
+	if err != nil {
+		return "", err
+	}
+
+	logger.Subprocess("Described the probe in the %s label", HealthCheckLabelName)
+
+	return label, nil
+}
+
+func proxy(context packit.BuildContext, logger scribe.Emitter, server webserverselector.Server, routes []Route) error {
+	logger.Process("Forwarding proxy routes")
+	for _, route := range routes {
+		logger.Subprocess("%s -> %s", route.Path, route.URL)
+	}
+
+	var fragments map[serverconf.Context][]byte
+	switch server.Name {
+	case "nginx":
+		fragments = NginxProxy(context.BuildpackInfo.ID, routes)
+	case "httpd":
+		fragments = map[serverconf.Context][]byte{
+			serverconf.Httpd: HttpdProxy(context.BuildpackInfo.ID, routes),
+		}
+	default:
+		return unsupported(ProxyEnv, server)
+	}
+
+	return writeFragments(context.WorkingDir, logger, ProxyFragment, fragments)
+}
+
+func precompress(context packit.BuildContext, logger scribe.Emitter, server webserverselector.Server, encodings []Encoding) error {
+	if server.Name != "nginx" && server.Name != "httpd" {
+		return unsupported(PrecompressEnv, server)
+	}
+
+	root, reason, err := servedRoot(context.WorkingDir, server)
+	if err != nil {
+		return err
+	}
+
+	logger.Process("Precompressing text assets in %s (%s)", root, reason)
+
+	written, err := Precompress(filepath.Join(context.WorkingDir, root), encodings)
+	if err != nil {
+		return err
+	}
+
+	for _, encoding := range encodings {
+		logger.Subprocess("Wrote %d %s files", written[encoding.Name], encoding.Extension)
+	}
+
+	var fragments map[serverconf.Context][]byte
+	switch server.Name {
+	case "nginx":
+		fragments = NginxPrecompress(context.BuildpackInfo.ID, encodings)
+	case "httpd":
+		fragments = map[serverconf.Context][]byte{
+			serverconf.Httpd: HttpdPrecompress(context.BuildpackInfo.ID, encodings),
+		}
+	}
+
+	return writeFragments(context.WorkingDir, logger, PrecompressFragment, fragments)
+}
+
+// servedRoot returns the directory that the server serves: the root of the
+// configuration provided by the app, the build output of a frontend, or the
+// first of StaticRoots that exists.
+func servedRoot(workingDir string, server webserverselector.Server) (string, string, error) {
+	root, found, err := serverconf.Root(workingDir, server.Name)
+	if err != nil {
+		return "", "", err
+	}
+
+	if found {
+		return root, fmt.Sprintf("the root of %s", server.ConfigFile), nil
+	}
+
+	root, reason, findErr := docroot.Find(workingDir)
+	if findErr == nil {
+		return root, reason, nil
+	}
+
+	if _, ok := os.LookupEnv("BP_WEB_SERVER_ROOT"); ok {
+		return "", "", findErr
+	}
+
+	for _, dir := range StaticRoots {
+		info, err := os.Stat(filepath.Join(workingDir, dir))
+		if err == nil && info.IsDir() {
+			return dir, fmt.Sprintf("%s/ was found", dir), nil
+		}
+	}
+
+	return "", "", fmt.Errorf("failed to find the document root: %s has no root, none of %s contains index.html and none of %s exists; set BP_WEB_SERVER_ROOT to the directory to serve", server.ConfigFile, strings.Join(docroot.OutputDirectories, ", "), strings.Join(StaticRoots, ", "))
+}
+
+func securityHeaders(context packit.BuildContext, logger scribe.Emitter, server webserverselector.Server, profile string, headers []Header) error {
+	logger.Process("Setting security headers (%s profile)", profile)
+	for _, header := range headers {
+		logger.Subprocess("%s: %s", header.Name, header.Value)
+	}
+
+	var fragments map[serverconf.Context][]byte
+	switch server.Name {
+	case "nginx":
+		fragments = NginxSecurityHeaders(context.BuildpackInfo.ID, headers)
+	case "httpd":
+		fragments = map[serverconf.Context][]byte{
+			serverconf.Httpd: HttpdSecurityHeaders(context.BuildpackInfo.ID, headers),
+		}
+	default:
+		return unsupported(SecurityHeadersEnv, server)
+	}
+
+	return writeFragments(context.WorkingDir, logger, SecurityHeadersFragment, fragments)
+}
+
+// statusPage serves the status page of the server on StatusAddress, for the
+// MetricsProcess to read.
+func statusPage(context packit.BuildContext, logger scribe.Emitter, server webserverselector.Server) error {
+	logger.Process("Serving the status of %s on %s", server.Name, StatusAddress)
+
+	var fragments map[serverconf.Context][]byte
+	switch server.Name {
+	case "nginx":
+		fragments = NginxStatus(context.BuildpackInfo.ID)
+	case "httpd":
+		fragments = map[serverconf.Context][]byte{
+			serverconf.Httpd: HttpdStatus(context.BuildpackInfo.ID),
+		}
+	default:
+		return unsupported(MetricsEnv, server)
+	}
+
+	return writeFragments(context.WorkingDir, logger, MetricsFragment, fragments)
+}
+
+func writeFragments(workingDir string, logger scribe.Emitter, name string, fragments map[serverconf.Context][]byte) error {
+	for _, fragmentContext := range slices.Sorted(maps.Keys(fragments)) {
+		err := serverconf.Write(workingDir, fragmentContext, name, fragments[fragmentContext])
+		if err != nil {
+			return err
+		}
+
+		logger.Subprocess("Wrote %s/%s/%s.conf", serverconf.Dir, fragmentContext, name)
+	}
+
+	return nil
+}
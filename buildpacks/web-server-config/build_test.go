@@ -0,0 +1,591 @@
+package webserverconfig_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	webserverconfig "github.com/paketo-buildpacks/web-servers/buildpacks/web-server-config"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testBuild(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+		layersDir  string
+		buffer     *bytes.Buffer
+		build      packit.BuildFunc
+		buildCtx   packit.BuildContext
+	)
+
+	it.Before(func() {
+		workingDir = t.TempDir()
+		layersDir = t.TempDir()
+		buffer = bytes.NewBuffer(nil)
+
+		Expect(os.MkdirAll(filepath.Join(workingDir, "build"), os.ModePerm)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(workingDir, "build", "index.html"), []byte(strings.Repeat("<p>some-content</p>\n", 100)), 0600)).To(Succeed())
+
+		build = webserverconfig.Build(scribe.NewEmitter(buffer))
+		buildCtx = packit.BuildContext{
+			BuildpackInfo: packit.Info{
+				ID:      "some-org/web-server-config",
+				Name:    "Some Buildpack",
+				Version: "some-version",
+			},
+			CNBPath:    "/cnb/buildpacks/some-buildpack",
+			WorkingDir: workingDir,
+			Layers:     packit.Layers{Path: layersDir},
+			Plan: packit.BuildpackPlan{
+				Entries: []packit.BuildpackPlanEntry{
+					{
+						Name:     "web-server-selection",
+						Metadata: map[string]interface{}{"server": "nginx"},
+					},
+				},
+			},
+		}
+	})
+
+	context("when BP_WEB_SERVER_PRECOMPRESS is set", func() {
+		it.Before(func() {
+			t.Setenv("BP_WEB_SERVER_PRECOMPRESS", "gzip,br")
+		})
+
+		it("precompresses the build output and writes the nginx fragment", func() {
+			result, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(packit.BuildResult{}))
+
+			Expect(filepath.Join(workingDir, "build", "index.html.br")).To(BeARegularFile())
+			Expect(filepath.Join(workingDir, "build", "index.html.gz")).To(BeARegularFile())
+
+			fragment, err := os.ReadFile(filepath.Join(workingDir, "web-servers", "nginx", "server", "20-precompress.conf"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(fragment)).To(ContainSubstring("gzip_static on;"))
+			Expect(string(fragment)).To(ContainSubstring("rewrite ^ $uri.br last;"))
+
+			Expect(buffer.String()).To(ContainSubstring("Some Buildpack some-version"))
+			Expect(buffer.String()).To(ContainSubstring("Precompressing text assets in build (build/index.html was found)"))
+			Expect(buffer.String()).To(ContainSubstring("Wrote 1 .br files"))
+			Expect(buffer.String()).To(ContainSubstring("Wrote 1 .gz files"))
+			Expect(buffer.String()).To(ContainSubstring("Wrote web-servers/nginx/server/20-precompress.conf"))
+		})
+
+		context("and the app provides an nginx.conf", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "nginx.conf"), []byte("http {\n  server {\n    root build;\n  }\n}\n"), 0600)).To(Succeed())
+			})
+
+			it("includes the fragments in it", func() {
+				_, err := build(buildCtx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(buffer.String()).To(ContainSubstring("Precompressing text assets in build (the root of nginx.conf)"))
+
+				config, err := os.ReadFile(filepath.Join(workingDir, "nginx.conf"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(config)).To(ContainSubstring("  server {\n    include web-servers/nginx/server/*.conf;\n"))
+
+				Expect(buffer.String()).To(ContainSubstring("Included the configuration fragments in nginx.conf"))
+			})
+		})
+
+		context("and the app provides an httpd.conf", func() {
+			it.Before(func() {
+				buildCtx.Plan.Entries[0].Metadata["server"] = "httpd"
+				Expect(os.WriteFile(filepath.Join(workingDir, "httpd.conf"), []byte(`DocumentRoot "${APP_ROOT}/build"`), 0600)).To(Succeed())
+			})
+
+			it("writes the httpd fragment and includes it", func() {
+				_, err := build(buildCtx)
+				Expect(err).NotTo(HaveOccurred())
+
+				fragment, err := os.ReadFile(filepath.Join(workingDir, "web-servers", "httpd", "20-precompress.conf"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(fragment)).To(ContainSubstring(`RewriteRule "^(.*)$" "$1.br" [PT,L]`))
+
+				config, err := os.ReadFile(filepath.Join(workingDir, "httpd.conf"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(config)).To(ContainSubstring(`IncludeOptional "${APP_ROOT}/web-servers/httpd/*.conf"`))
+
+				Expect(filepath.Join(workingDir, "web-servers", "nginx")).NotTo(BeAnExistingFile())
+			})
+		})
+
+		context("and a static app provides an nginx.conf serving public/", func() {
+			it.Before(func() {
+				Expect(os.RemoveAll(filepath.Join(workingDir, "build"))).To(Succeed())
+				Expect(os.MkdirAll(filepath.Join(workingDir, "public"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "public", "index.html"), []byte(strings.Repeat("<p>some-content</p>\n", 100)), 0600)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "nginx.conf"), []byte("http {\n  include custom.conf;\n}\n"), 0600)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "custom.conf"), []byte("server {\n  listen {{port}};\n  root public;\n}\n"), 0600)).To(Succeed())
+			})
+
+			it("precompresses the root of the configuration", func() {
+				_, err := build(buildCtx)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(filepath.Join(workingDir, "public", "index.html.br")).To(BeARegularFile())
+				Expect(buffer.String()).To(ContainSubstring("Precompressing text assets in public (the root of nginx.conf)"))
+			})
+		})
+
+		context("and a static app provides an httpd.conf serving htdocs/", func() {
+			it.Before(func() {
+				buildCtx.Plan.Entries[0].Metadata["server"] = "httpd"
+				Expect(os.RemoveAll(filepath.Join(workingDir, "build"))).To(Succeed())
+				Expect(os.MkdirAll(filepath.Join(workingDir, "htdocs"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "htdocs", "index.html"), []byte(strings.Repeat("<p>some-content</p>\n", 100)), 0600)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "httpd.conf"), []byte(`DocumentRoot "${APP_ROOT}/htdocs"`), 0600)).To(Succeed())
+			})
+
+			it("precompresses the DocumentRoot", func() {
+				_, err := build(buildCtx)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(filepath.Join(workingDir, "htdocs", "index.html.gz")).To(BeARegularFile())
+				Expect(buffer.String()).To(ContainSubstring("Precompressing text assets in htdocs (the root of httpd.conf)"))
+			})
+		})
+
+		context("and a static site without configuration has public/", func() {
+			it.Before(func() {
+				Expect(os.RemoveAll(filepath.Join(workingDir, "build"))).To(Succeed())
+				Expect(os.MkdirAll(filepath.Join(workingDir, "public"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "public", "index.html"), []byte(strings.Repeat("<p>some-content</p>\n", 100)), 0600)).To(Succeed())
+			})
+
+			it("precompresses the directory the server serves by convention", func() {
+				_, err := build(buildCtx)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(filepath.Join(workingDir, "public", "index.html.br")).To(BeARegularFile())
+				Expect(buffer.String()).To(ContainSubstring("Precompressing text assets in public (public/ was found)"))
+			})
+		})
+	})
+
+	context("when BP_WEB_SERVER_SECURITY_HEADERS is set", func() {
+		it.Before(func() {
+			t.Setenv("BP_WEB_SERVER_SECURITY_HEADERS", "default")
+			t.Setenv("BP_WEB_SERVER_HEADER_X_FRAME_OPTIONS", "DENY")
+		})
+
+		it("writes the headers fragment without needing build output", func() {
+			Expect(os.RemoveAll(filepath.Join(workingDir, "build"))).To(Succeed())
+
+			_, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+
+			fragment, err := os.ReadFile(filepath.Join(workingDir, "web-servers", "nginx", "headers", "30-security-headers.conf"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(fragment)).To(ContainSubstring(`add_header X-Content-Type-Options "nosniff" always;`))
+			Expect(string(fragment)).To(ContainSubstring(`add_header X-Frame-Options "DENY" always;`))
+
+			Expect(buffer.String()).To(ContainSubstring("Setting security headers (default profile)"))
+			Expect(buffer.String()).To(ContainSubstring("X-Frame-Options: DENY"))
+			Expect(buffer.String()).NotTo(ContainSubstring("Precompressing"))
+		})
+
+		context("and the app provides an httpd.conf", func() {
+			it.Before(func() {
+				buildCtx.Plan.Entries[0].Metadata["server"] = "httpd"
+				Expect(os.WriteFile(filepath.Join(workingDir, "httpd.conf"), []byte(`DocumentRoot "${APP_ROOT}/htdocs"`), 0600)).To(Succeed())
+			})
+
+			it("writes the httpd fragment", func() {
+				_, err := build(buildCtx)
+				Expect(err).NotTo(HaveOccurred())
+
+				fragment, err := os.ReadFile(filepath.Join(workingDir, "web-servers", "httpd", "30-security-headers.conf"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(fragment)).To(ContainSubstring(`Header always set X-Frame-Options "DENY"`))
+			})
+		})
+
+		context("and the profile is off", func() {
+			it.Before(func() {
+				t.Setenv("BP_WEB_SERVER_SECURITY_HEADERS", "off")
+				t.Setenv("BP_WEB_SERVER_HEADER_X_FRAME_OPTIONS", "")
+			})
+
+			it("writes nothing", func() {
+				_, err := build(buildCtx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(filepath.Join(workingDir, "web-servers")).NotTo(BeAnExistingFile())
+			})
+		})
+	})
+
+	context("when BP_WEB_SERVER_HEALTH_CHECK is set", func() {
+		it.Before(func() {
+			t.Setenv("BP_WEB_SERVER_HEALTH_CHECK", "true")
+		})
+
+		it("writes the nginx fragment and labels the image", func() {
+			result, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Launch.Labels).To(Equal(map[string]string{
+				"io.paketo.web-servers.health-check": `{"path":"/healthz","port-env":"PORT","type":"http"}`,
+			}))
+
+			fragment, err := os.ReadFile(filepath.Join(workingDir, "web-servers", "nginx", "server", "00-health-check.conf"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(fragment)).To(ContainSubstring("location = /healthz {"))
+
+			Expect(buffer.String()).To(ContainSubstring("Answering health checks at /healthz"))
+		})
+
+		context("and the app provides an httpd.conf", func() {
+			it.Before(func() {
+				buildCtx.Plan.Entries[0].Metadata["server"] = "httpd"
+				Expect(os.WriteFile(filepath.Join(workingDir, "httpd.conf"), []byte(`DocumentRoot "${APP_ROOT}/htdocs"`), 0600)).To(Succeed())
+			})
+
+			it("writes the httpd fragment and the response", func() {
+				_, err := build(buildCtx)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(filepath.Join(workingDir, "web-servers", "httpd", "00-health-check.conf")).To(BeARegularFile())
+
+				response, err := os.ReadFile(filepath.Join(workingDir, "web-servers", "healthz"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(response)).To(Equal("ok"))
+			})
+		})
+
+		context("and the app provides a Caddyfile", func() {
+			it.Before(func() {
+				buildCtx.Plan.Entries[0].Metadata["server"] = "caddy"
+				Expect(os.WriteFile(filepath.Join(workingDir, "Caddyfile"), []byte(":{$PORT} {\n\tfile_server\n}\n"), 0600)).To(Succeed())
+			})
+
+			it("writes the caddy fragment and imports it", func() {
+				_, err := build(buildCtx)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(filepath.Join(workingDir, "web-servers", "caddy", "00-health-check.conf")).To(BeARegularFile())
+
+				config, err := os.ReadFile(filepath.Join(workingDir, "Caddyfile"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(config)).To(ContainSubstring("import web-servers/caddy/*.conf"))
+			})
+		})
+
+		context("and basic authentication is enabled", func() {
+			it.Before(func() {
+				t.Setenv("BP_WEB_SERVER_BASIC_AUTH", "true")
+			})
+
+			it("leaves the health check open", func() {
+				result, err := build(buildCtx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Layers).To(HaveLen(1))
+				Expect(result.Layers[0].LaunchEnv).To(HaveKeyWithValue("WEB_SERVER_HEALTH_CHECK_PATH.override", "/healthz"))
+			})
+		})
+	})
+
+	context("when proxy routes are declared", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(workingDir, "web-servers.toml"), []byte(`
+[[proxy]]
+path = "/api"
+url = "http://backend:8080"
+`), 0600)).To(Succeed())
+		})
+
+		it("writes the proxy fragment", func() {
+			_, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+
+			fragment, err := os.ReadFile(filepath.Join(workingDir, "web-servers", "nginx", "server", "05-proxy.conf"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(fragment)).To(ContainSubstring("proxy_pass http://backend:8080/api/;"))
+
+			Expect(buffer.String()).To(ContainSubstring("Forwarding proxy routes"))
+			Expect(buffer.String()).To(ContainSubstring("/api -> http://backend:8080"))
+		})
+
+		context("and the app provides an httpd.conf", func() {
+			it.Before(func() {
+				buildCtx.Plan.Entries[0].Metadata["server"] = "httpd"
+				t.Setenv("BP_WEB_SERVER_PROXY", "/api=http://other-backend:8080")
+				Expect(os.WriteFile(filepath.Join(workingDir, "httpd.conf"), []byte(`DocumentRoot "${APP_ROOT}/htdocs"`), 0600)).To(Succeed())
+			})
+
+			it("writes the httpd fragment with the route from the environment", func() {
+				_, err := build(buildCtx)
+				Expect(err).NotTo(HaveOccurred())
+
+				fragment, err := os.ReadFile(filepath.Join(workingDir, "web-servers", "httpd", "05-proxy.conf"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(fragment)).To(ContainSubstring(`RewriteRule "^/api(/.*)?$" "http://other-backend:8080/api$1" [P,L]`))
+			})
+		})
+	})
+
+	context("when BP_WEB_SERVER_BASIC_AUTH is set", func() {
+		it.Before(func() {
+			t.Setenv("BP_WEB_SERVER_BASIC_AUTH", "true")
+			Expect(os.WriteFile(filepath.Join(workingDir, "nginx.conf"), []byte("http {\n  server {\n  }\n}\n"), 0600)).To(Succeed())
+		})
+
+		it("contributes a launch layer with the exec.d helper", func() {
+			result, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers).To(HaveLen(1))
+			layer := result.Layers[0]
+			Expect(layer.Name).To(Equal("web-server-config"))
+			Expect(layer.Launch).To(BeTrue())
+			Expect(layer.Build).To(BeFalse())
+			Expect(layer.ExecD).To(Equal([]string{"/cnb/buildpacks/some-buildpack/bin/configure-launch"}))
+			Expect(layer.LaunchEnv).To(Equal(packit.Environment{
+				"WEB_SERVER_NAME.override":       "nginx",
+				"WEB_SERVER_APP_ROOT.override":   workingDir,
+				"WEB_SERVER_BASIC_AUTH.override": "true",
+			}))
+
+			config, err := os.ReadFile(filepath.Join(workingDir, "nginx.conf"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(config)).To(ContainSubstring("include web-servers/nginx/server/*.conf;"))
+
+			Expect(buffer.String()).To(ContainSubstring("Enabling basic authentication at launch"))
+			Expect(buffer.String()).To(ContainSubstring("Requires the users in a service binding of type htpasswd, when one is bound"))
+		})
+
+		context("to false", func() {
+			it.Before(func() {
+				t.Setenv("BP_WEB_SERVER_BASIC_AUTH", "false")
+			})
+
+			it("contributes no layer", func() {
+				result, err := build(buildCtx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Layers).To(BeEmpty())
+			})
+		})
+	})
+
+	context("when BP_WEB_SERVER_TLS is set", func() {
+		it.Before(func() {
+			t.Setenv("BP_WEB_SERVER_TLS", "true")
+		})
+
+		it("enables TLS in the launch layer", func() {
+			result, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers).To(HaveLen(1))
+			Expect(result.Layers[0].ExecD).To(Equal([]string{"/cnb/buildpacks/some-buildpack/bin/configure-launch"}))
+			Expect(result.Layers[0].LaunchEnv).To(Equal(packit.Environment{
+				"WEB_SERVER_NAME.override":     "nginx",
+				"WEB_SERVER_APP_ROOT.override": workingDir,
+				"WEB_SERVER_TLS.override":      "true",
+			}))
+
+			Expect(buffer.String()).To(ContainSubstring("Enabling TLS at launch"))
+		})
+
+		context("and the selected server is caddy", func() {
+			it.Before(func() {
+				buildCtx.Plan.Entries[0].Metadata["server"] = "caddy"
+				Expect(os.WriteFile(filepath.Join(workingDir, "Caddyfile"), []byte(":{$PORT} {\n\tfile_server\n}\n"), 0600)).To(Succeed())
+			})
+
+			it("enables TLS in the launch layer and imports the fragments", func() {
+				result, err := build(buildCtx)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(result.Layers).To(HaveLen(1))
+				Expect(result.Layers[0].LaunchEnv).To(HaveKeyWithValue("WEB_SERVER_NAME.override", "caddy"))
+				Expect(result.Layers[0].LaunchEnv).To(HaveKeyWithValue("WEB_SERVER_TLS.override", "true"))
+
+				config, err := os.ReadFile(filepath.Join(workingDir, "Caddyfile"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(config)).To(ContainSubstring("import web-servers/caddy/*.conf"))
+			})
+		})
+
+		context("and basic authentication is enabled", func() {
+			it.Before(func() {
+				t.Setenv("BP_WEB_SERVER_BASIC_AUTH", "true")
+			})
+
+			it("enables both in a single launch layer", func() {
+				result, err := build(buildCtx)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(result.Layers).To(HaveLen(1))
+				Expect(result.Layers[0].LaunchEnv).To(HaveKeyWithValue("WEB_SERVER_BASIC_AUTH.override", "true"))
+				Expect(result.Layers[0].LaunchEnv).To(HaveKeyWithValue("WEB_SERVER_TLS.override", "true"))
+			})
+		})
+	})
+
+	context("when BP_WEB_SERVER_METRICS is set", func() {
+		it.Before(func() {
+			t.Setenv("BP_WEB_SERVER_METRICS", "true")
+		})
+
+		it("serves the status page and adds the metrics process", func() {
+			result, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers).To(BeEmpty())
+			Expect(result.Launch.Processes).To(Equal([]packit.Process{
+				{
+					Type:    "metrics",
+					Command: "/cnb/buildpacks/some-buildpack/bin/metrics",
+					Args:    []string{"nginx"},
+					Direct:  true,
+				},
+			}))
+
+			Expect(filepath.Join(workingDir, "web-servers", "nginx", "http", "60-metrics.conf")).To(BeARegularFile())
+
+			Expect(buffer.String()).To(ContainSubstring("Serving the status of nginx on 127.0.0.1:18081"))
+			Expect(buffer.String()).To(ContainSubstring("Added the metrics process, which runs the web process and serves Prometheus metrics next to it on $METRICS_PORT (default 9113)"))
+		})
+
+		context("and the selected server is httpd", func() {
+			it.Before(func() {
+				buildCtx.Plan.Entries[0].Metadata["server"] = "httpd"
+			})
+
+			it("writes the httpd fragment", func() {
+				result, err := build(buildCtx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Launch.Processes).To(HaveLen(1))
+				Expect(result.Launch.Processes[0].Args).To(Equal([]string{"httpd"}))
+
+				Expect(filepath.Join(workingDir, "web-servers", "httpd", "60-metrics.conf")).To(BeARegularFile())
+			})
+		})
+	})
+
+	context("failure cases", func() {
+		context("when no web server was selected", func() {
+			it.Before(func() {
+				buildCtx.Plan.Entries = nil
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(ContainSubstring("no web server was selected")))
+			})
+		})
+
+		context("when BP_WEB_SERVER_PRECOMPRESS is invalid", func() {
+			it.Before(func() {
+				t.Setenv("BP_WEB_SERVER_PRECOMPRESS", "deflate")
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(`unsupported BP_WEB_SERVER_PRECOMPRESS encoding "deflate": expected gzip or br`))
+			})
+		})
+
+		context("when there is no build output", func() {
+			it.Before(func() {
+				t.Setenv("BP_WEB_SERVER_PRECOMPRESS", "gzip")
+				Expect(os.RemoveAll(filepath.Join(workingDir, "build"))).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError("failed to find the document root: nginx.conf has no root, none of build, dist, out contains index.html and none of public, htdocs exists; set BP_WEB_SERVER_ROOT to the directory to serve"))
+			})
+		})
+
+		context("when BP_WEB_SERVER_SECURITY_HEADERS is invalid", func() {
+			it.Before(func() {
+				t.Setenv("BP_WEB_SERVER_SECURITY_HEADERS", "paranoid")
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(ContainSubstring(`unsupported BP_WEB_SERVER_SECURITY_HEADERS profile "paranoid"`)))
+			})
+		})
+
+		context("when a proxy route is invalid", func() {
+			it.Before(func() {
+				t.Setenv("BP_WEB_SERVER_PROXY", "/api=ftp://backend")
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(`invalid proxy URL "ftp://backend" for /api: expected an http or https URL`))
+			})
+		})
+
+		context("when BP_WEB_SERVER_BASIC_AUTH is not a boolean", func() {
+			it.Before(func() {
+				t.Setenv("BP_WEB_SERVER_BASIC_AUTH", "some-value")
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(ContainSubstring("failed to parse BP_WEB_SERVER_BASIC_AUTH")))
+			})
+		})
+
+		context("when a feature is not supported for the selected server", func() {
+			it.Before(func() {
+				t.Setenv("BP_WEB_SERVER_PRECOMPRESS", "gzip")
+				buildCtx.Plan.Entries[0].Metadata["server"] = "caddy"
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError("BP_WEB_SERVER_PRECOMPRESS is not supported for caddy"))
+			})
+		})
+
+		context("when basic authentication is not supported for the selected server", func() {
+			it.Before(func() {
+				t.Setenv("BP_WEB_SERVER_BASIC_AUTH", "true")
+				buildCtx.Plan.Entries[0].Metadata["server"] = "caddy"
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError("BP_WEB_SERVER_BASIC_AUTH is not supported for caddy"))
+			})
+		})
+
+		context("when metrics are not supported for the selected server", func() {
+			it.Before(func() {
+				t.Setenv("BP_WEB_SERVER_METRICS", "true")
+				buildCtx.Plan.Entries[0].Metadata["server"] = "caddy"
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError("BP_WEB_SERVER_METRICS is not supported for caddy"))
+			})
+		})
+
+		context("when BP_WEB_SERVER_HEALTH_CHECK is not a boolean", func() {
+			it.Before(func() {
+				t.Setenv("BP_WEB_SERVER_HEALTH_CHECK", "some-value")
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(ContainSubstring("failed to parse BP_WEB_SERVER_HEALTH_CHECK")))
+			})
+		})
+	})
+}
@@ -0,0 +1,14 @@
+package main_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitConfigureLaunch(t *testing.T) {
+	suite := spec.New("configure-launch", spec.Report(report.Terminal{}), spec.Sequential())
+	suite("ConfigureLaunch", testConfigureLaunch)
+	suite.Run(t)
+}
@@ -0,0 +1,118 @@
+// Command configure-launch is the exec.d helper contributed by the
+// web-server-config buildpack. The launcher runs it before the web server
+// starts, so features configured from service bindings follow the bindings of
+// each container.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/paketo-buildpacks/packit/v2/servicebindings"
+	webserverconfig "github.com/paketo-buildpacks/web-servers/buildpacks/web-server-config"
+	"github.com/paketo-buildpacks/web-servers/internal/serverconf"
+)
+
+func main() {
+	err := run()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	server := os.Getenv(webserverconfig.ServerEnv)
+	appRoot := os.Getenv(webserverconfig.AppRootEnv)
+	if server == "" || appRoot == "" {
+		return fmt.Errorf("%s and %s must be set", webserverconfig.ServerEnv, webserverconfig.AppRootEnv)
+	}
+
+	resolver := servicebindings.NewResolver()
+
+	if os.Getenv(webserverconfig.BasicAuthFlagEnv) == "true" {
+		bindings, err := resolve(resolver, webserverconfig.HtpasswdBindingType)
+		if err != nil {
+			return err
+		}
+
+		var open []string
+		if path := os.Getenv(webserverconfig.HealthCheckPathEnv); path != "" {
+			open = append(open, path)
+		}
+
+		fragments, err := webserverconfig.BasicAuth(server, bindings, open)
+		if err != nil {
+			return err
+		}
+
+		err = write(appRoot, webserverconfig.BasicAuthFragment, fragments)
+		if err != nil {
+			return err
+		}
+	}
+
+	if os.Getenv(webserverconfig.TLSFlagEnv) == "true" {
+		bindings, err := resolve(resolver, webserverconfig.TLSBindingType)
+		if err != nil {
+			return err
+		}
+
+		fragments, err := webserverconfig.TLS(server, bindings)
+		if err != nil {
+			return err
+		}
+
+		err = write(appRoot, webserverconfig.TLSFragment, fragments)
+		if err != nil {
+			return err
+		}
+
+		if server == "nginx" && len(fragments) > 0 {
+			found, err := serverconf.ListenSSL(appRoot)
+			if err != nil {
+				return err
+			}
+
+			if !found {
+				return errors.New("failed to enable TLS: no listen directive for {{port}} found in nginx.conf or the files it includes")
+			}
+		}
+	}
+
+	return nil
+}
+
+func resolve(resolver *servicebindings.Resolver, bindingType string) ([]servicebindings.Binding, error) {
+	bindings, err := resolver.Resolve(bindingType, "", "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s service bindings: %w", bindingType, err)
+	}
+
+	return bindings, nil
+}
+
+// write replaces the fragments of a feature, removing those left by an
+// earlier start of the container when the feature is no longer configured.
+func write(appRoot, name string, fragments map[serverconf.Context][]byte) error {
+	for _, context := range serverconf.Contexts {
+		content, ok := fragments[context]
+		if !ok {
+			err := os.Remove(filepath.Join(appRoot, serverconf.Dir, string(context), name+".conf"))
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("failed to remove %s fragment: %w", name, err)
+			}
+
+			continue
+		}
+
+		err := serverconf.Write(appRoot, context, name, content)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package main_test
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega/gexec"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testConfigureLaunch(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		helper  string
+		appRoot string
+	)
+
+	it.Before(func() {
+		var err error
+		helper, err = gexec.Build("github.com/paketo-buildpacks/web-servers/buildpacks/web-server-config/configure-launch")
+		Expect(err).NotTo(HaveOccurred())
+
+		appRoot = t.TempDir()
+	})
+
+	it.After(func() {
+		gexec.CleanupBuildArtifacts()
+	})
+
+	it("closes fd 3 when it exits, so the launcher can start the web server", func() {
+		reader, writer, err := os.Pipe()
+		Expect(err).NotTo(HaveOccurred())
+		defer reader.Close()
+
+		cmd := exec.Command(helper)
+		cmd.Env = append(os.Environ(),
+			"WEB_SERVER_NAME=nginx",
+			"WEB_SERVER_APP_ROOT="+appRoot,
+			"WEB_SERVER_BASIC_AUTH=true",
+			"WEB_SERVER_TLS=true",
+			"SERVICE_BINDING_ROOT="+t.TempDir(),
+		)
+		cmd.ExtraFiles = []*os.File{writer}
+		Expect(cmd.Run()).To(Succeed())
+		Expect(writer.Close()).To(Succeed())
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := io.ReadAll(reader)
+			done <- err
+		}()
+
+		Eventually(done, 5*time.Second).Should(Receive(BeNil()))
+
+		Expect(filepath.Join(appRoot, "web-servers")).NotTo(BeADirectory())
+	})
+}
@@ -0,0 +1,158 @@
+package webserverconfig
+
+// PrecompressEnv lists the encodings that text assets are precompressed with
+// at build time.
+const PrecompressEnv = "BP_WEB_SERVER_PRECOMPRESS"
+
+// SecurityHeadersEnv names the profile of security headers set on every
+// response: strict, default or off.
+const SecurityHeadersEnv = "BP_WEB_SERVER_SECURITY_HEADERS"
+
+// HeaderEnvPrefix starts the environment variables that override or add a
+// single security header.
+const HeaderEnvPrefix = "BP_WEB_SERVER_HEADER_"
+
+// BasicAuthEnv enables HTTP basic authentication from a service binding of
+// HtpasswdBindingType at launch.
+const BasicAuthEnv = "BP_WEB_SERVER_BASIC_AUTH"
+
+// ProxyEnv lists the routes that are forwarded to a backend, as path=url
+// pairs. They can also be declared in ConfigFile.
+const ProxyEnv = "BP_WEB_SERVER_PROXY"
+
+// ConfigFile is the file in the app root that declares proxy routes.
+const ConfigFile = "web-servers.toml"
+
+// HealthCheckEnv enables a response at HealthCheckPath that does not depend
+// on the document root.
+const HealthCheckEnv = "BP_WEB_SERVER_HEALTH_CHECK"
+
+// HealthCheckPath is the path of the health check.
+const HealthCheckPath = "/healthz"
+
+// HealthCheckFile is the response body that HTTPD serves for the health
+// check, written below serverconf.Dir.
+const HealthCheckFile = "healthz"
+
+// HealthCheckLabelName is the image label that describes the health check.
+const HealthCheckLabelName = "io.paketo.web-servers.health-check"
+
+// HtpasswdBindingType is the type of the service binding that holds the
+// htpasswd file, as its "htpasswd" entry.
+const HtpasswdBindingType = "htpasswd"
+
+// TLSEnv enables TLS on the listener for $PORT at launch, with the
+// certificate from a service binding of TLSBindingType.
+const TLSEnv = "BP_WEB_SERVER_TLS"
+
+// TLSBindingType is the type of the service binding that holds the
+// certificate chain and its private key, as the entries of a Kubernetes TLS
+// secret.
+const TLSBindingType = "tls"
+
+// The entries of a service binding of TLSBindingType.
+const (
+	TLSCertificateEntry = "tls.crt"
+	TLSKeyEntry         = "tls.key"
+)
+
+// MetricsEnv enables the MetricsProcess, which exposes the status of the web
+// server as Prometheus metrics.
+const MetricsEnv = "BP_WEB_SERVER_METRICS"
+
+// MetricsProcess is the type of the launch process, built from metrics/, that
+// serves the metrics. It starts WebProcess as its child and reads the status
+// page over loopback, so the web server and its metrics run in one container.
+const MetricsProcess = "metrics"
+
+// WebProcess is the launcher entry of the default process that the web
+// server's buildpack contributes, which the MetricsProcess runs.
+const WebProcess = "/cnb/process/web"
+
+// MetricsPortEnv is the port that the MetricsProcess listens on, which is
+// DefaultMetricsPort when unset.
+const (
+	MetricsPortEnv     = "METRICS_PORT"
+	DefaultMetricsPort = "9113"
+)
+
+// StatusAddress is the loopback address that the web server serves its status
+// page on, so the page is only reachable from its network namespace.
+const StatusAddress = "127.0.0.1:18081"
+
+// LayerName is the launch layer that holds the exec.d helper.
+const LayerName = "web-server-config"
+
+// HelperName is the exec.d executable, built from configure-launch/, that
+// writes the fragments of launch-time features before the web server starts.
+const HelperName = "configure-launch"
+
+// The helper reads its configuration from these launch environment variables,
+// which Build sets.
+const (
+	ServerEnv        = "WEB_SERVER_NAME"
+	AppRootEnv       = "WEB_SERVER_APP_ROOT"
+	BasicAuthFlagEnv = "WEB_SERVER_BASIC_AUTH"
+	TLSFlagEnv       = "WEB_SERVER_TLS"
+
+	// HealthCheckPathEnv is set when the health check is enabled, so that
+	// basic authentication leaves it open.
+	HealthCheckPathEnv = "WEB_SERVER_HEALTH_CHECK_PATH"
+)
+
+// Settings are the environment variables that enable a feature of the
+// buildpack; it detects when any of them is set. Entries ending in * match
+// any variable with that prefix.
+var Settings = []string{PrecompressEnv, SecurityHeadersEnv, HeaderEnvPrefix + "*", BasicAuthEnv, ProxyEnv, HealthCheckEnv, TLSEnv, MetricsEnv}
+
+// HealthCheckFragment is the name of the configuration fragments that answer
+// the health check. It sorts first, so no other fragment handles the request.
+const HealthCheckFragment = "00-health-check"
+
+// ProxyFragment is the name of the configuration fragments that forward the
+// proxy routes. It sorts before the fragments whose rewrites would otherwise
+// take the requests for the routes.
+const ProxyFragment = "05-proxy"
+
+// PrecompressFragment is the name of the configuration fragments that serve
+// the precompressed siblings.
+const PrecompressFragment = "20-precompress"
+
+// SecurityHeadersFragment is the name of the configuration fragments that set
+// the security headers.
+const SecurityHeadersFragment = "30-security-headers"
+
+// BasicAuthFragment is the name of the configuration fragments that require
+// authentication, written at launch.
+const BasicAuthFragment = "40-basic-auth"
+
+// TLSFragment is the name of the configuration fragments that set the
+// certificate, written at launch.
+const TLSFragment = "50-tls"
+
+// MetricsFragment is the name of the configuration fragments that serve the
+// status page.
+const MetricsFragment = "60-metrics"
+
+// StaticRoots are the directories that the NGINX and HTTPD buildpacks serve
+// by convention, public/ by default and htdocs/ in the HTTPD samples. Assets
+// are precompressed in the first that exists when neither the configuration
+// of the app nor a frontend names the document root.
+var StaticRoots = []string{"public", "htdocs"}
+
+// TextAssets maps the extensions of the assets that are precompressed to
+// their media types. Images, fonts and archives are already compressed.
+var TextAssets = map[string]string{
+	".css":         "text/css",
+	".htm":         "text/html",
+	".html":        "text/html",
+	".js":          "application/javascript",
+	".json":        "application/json",
+	".map":         "application/json",
+	".mjs":         "application/javascript",
+	".svg":         "image/svg+xml",
+	".txt":         "text/plain",
+	".wasm":        "application/wasm",
+	".webmanifest": "application/manifest+json",
+	".xml":         "text/xml",
+}
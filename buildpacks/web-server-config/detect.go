@@ -0,0 +1,46 @@
+package webserverconfig
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	webserverselector "github.com/paketo-buildpacks/web-servers/buildpacks/web-server-selector"
+)
+
+// Detect passes when any of Settings is set or the app has a ConfigFile, as
+// each feature is opt-in. Its plan receives the server chosen by the web
+// server selector.
+func Detect() packit.DetectFunc {
+	return func(context packit.DetectContext) (packit.DetectResult, error) {
+		for _, setting := range Settings {
+			if prefix, ok := strings.CutSuffix(setting, "*"); ok {
+				for _, variable := range os.Environ() {
+					if strings.HasPrefix(variable, prefix) {
+						return packit.DetectResult{Plan: webserverselector.SelectionPlan()}, nil
+					}
+				}
+
+				continue
+			}
+
+			if strings.TrimSpace(os.Getenv(setting)) != "" {
+				return packit.DetectResult{Plan: webserverselector.SelectionPlan()}, nil
+			}
+		}
+
+		_, err := os.Stat(filepath.Join(context.WorkingDir, ConfigFile))
+		if err == nil {
+			return packit.DetectResult{Plan: webserverselector.SelectionPlan()}, nil
+		}
+
+		if !errors.Is(err, os.ErrNotExist) {
+			return packit.DetectResult{}, fmt.Errorf("failed to stat %s: %w", ConfigFile, err)
+		}
+
+		return packit.DetectResult{}, packit.Fail.WithMessage("none of %s is set and %s not found", strings.Join(Settings, ", "), ConfigFile)
+	}
+}
@@ -0,0 +1,156 @@
+package webserverconfig_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	webserverconfig "github.com/paketo-buildpacks/web-servers/buildpacks/web-server-config"
+	webserverselector "github.com/paketo-buildpacks/web-servers/buildpacks/web-server-selector"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDetect(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		detect packit.DetectFunc
+	)
+
+	it.Before(func() {
+		detect = webserverconfig.Detect()
+	})
+
+	context("when BP_WEB_SERVER_PRECOMPRESS is set", func() {
+		it.Before(func() {
+			t.Setenv("BP_WEB_SERVER_PRECOMPRESS", "gzip,br")
+		})
+
+		it("passes", func() {
+			result, err := detect(packit.DetectContext{
+				WorkingDir: t.TempDir(),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(packit.DetectResult{Plan: webserverselector.SelectionPlan()}))
+		})
+	})
+
+	context("when BP_WEB_SERVER_SECURITY_HEADERS is set", func() {
+		it.Before(func() {
+			t.Setenv("BP_WEB_SERVER_SECURITY_HEADERS", "strict")
+		})
+
+		it("passes", func() {
+			_, err := detect(packit.DetectContext{
+				WorkingDir: t.TempDir(),
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	context("when a single header is overridden", func() {
+		it.Before(func() {
+			t.Setenv("BP_WEB_SERVER_HEADER_X_FRAME_OPTIONS", "DENY")
+		})
+
+		it("passes", func() {
+			_, err := detect(packit.DetectContext{
+				WorkingDir: t.TempDir(),
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	context("when BP_WEB_SERVER_BASIC_AUTH is set", func() {
+		it.Before(func() {
+			t.Setenv("BP_WEB_SERVER_BASIC_AUTH", "true")
+		})
+
+		it("passes", func() {
+			_, err := detect(packit.DetectContext{
+				WorkingDir: t.TempDir(),
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	context("when BP_WEB_SERVER_PROXY is set", func() {
+		it.Before(func() {
+			t.Setenv("BP_WEB_SERVER_PROXY", "/api=http://backend:8080")
+		})
+
+		it("passes", func() {
+			_, err := detect(packit.DetectContext{
+				WorkingDir: t.TempDir(),
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	context("when BP_WEB_SERVER_HEALTH_CHECK is set", func() {
+		it.Before(func() {
+			t.Setenv("BP_WEB_SERVER_HEALTH_CHECK", "true")
+		})
+
+		it("passes", func() {
+			_, err := detect(packit.DetectContext{
+				WorkingDir: t.TempDir(),
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	context("when BP_WEB_SERVER_TLS is set", func() {
+		it.Before(func() {
+			t.Setenv("BP_WEB_SERVER_TLS", "true")
+		})
+
+		it("passes", func() {
+			_, err := detect(packit.DetectContext{
+				WorkingDir: t.TempDir(),
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	context("when BP_WEB_SERVER_METRICS is set", func() {
+		it.Before(func() {
+			t.Setenv("BP_WEB_SERVER_METRICS", "true")
+		})
+
+		it("passes", func() {
+			_, err := detect(packit.DetectContext{
+				WorkingDir: t.TempDir(),
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	context("when the app has a web-servers.toml", func() {
+		var workingDir string
+
+		it.Before(func() {
+			workingDir = t.TempDir()
+			Expect(os.WriteFile(filepath.Join(workingDir, "web-servers.toml"), nil, 0600)).To(Succeed())
+		})
+
+		it("passes", func() {
+			_, err := detect(packit.DetectContext{
+				WorkingDir: workingDir,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	context("when no feature is enabled", func() {
+		it("fails", func() {
+			_, err := detect(packit.DetectContext{
+				WorkingDir: t.TempDir(),
+			})
+			Expect(err).To(MatchError(packit.Fail.WithMessage("none of BP_WEB_SERVER_PRECOMPRESS, BP_WEB_SERVER_SECURITY_HEADERS, BP_WEB_SERVER_HEADER_*, BP_WEB_SERVER_BASIC_AUTH, BP_WEB_SERVER_PROXY, BP_WEB_SERVER_HEALTH_CHECK, BP_WEB_SERVER_TLS, BP_WEB_SERVER_METRICS is set and web-servers.toml not found")))
+		})
+	})
+}
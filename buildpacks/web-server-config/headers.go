@@ -0,0 +1,133 @@
+package webserverconfig
+
+import (
+	"bytes"
+	"fmt"
+	"net/textproto"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/paketo-buildpacks/web-servers/internal/serverconf"
+)
+
+// Header is a response header set on every response.
+type Header struct {
+	Name  string
+	Value string
+}
+
+// Profiles are the sets of security headers that SecurityHeadersEnv selects
+// from. The strict profile's Content-Security-Policy rejects inline scripts
+// and styles, which some frontend builds emit.
+var Profiles = map[string][]Header{
+	"off": nil,
+	"default": {
+		{Name: "Strict-Transport-Security", Value: "max-age=31536000"},
+		{Name: "X-Content-Type-Options", Value: "nosniff"},
+		{Name: "X-Frame-Options", Value: "SAMEORIGIN"},
+		{Name: "Referrer-Policy", Value: "strict-origin-when-cross-origin"},
+		{Name: "Content-Security-Policy", Value: "frame-ancestors 'self'"},
+	},
+	"strict": {
+		{Name: "Strict-Transport-Security", Value: "max-age=63072000; includeSubDomains"},
+		{Name: "X-Content-Type-Options", Value: "nosniff"},
+		{Name: "X-Frame-Options", Value: "DENY"},
+		{Name: "Referrer-Policy", Value: "no-referrer"},
+		{Name: "Content-Security-Policy", Value: "default-src 'self'; img-src 'self' data:; object-src 'none'; base-uri 'self'; form-action 'self'; frame-ancestors 'none'"},
+		{Name: "Permissions-Policy", Value: "camera=(), geolocation=(), microphone=()"},
+		{Name: "Cross-Origin-Opener-Policy", Value: "same-origin"},
+	},
+}
+
+var headerName = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+
+// SecurityHeaders returns the name of the profile selected in environ, given
+// in the "NAME=value" form of os.Environ, and its headers with the overrides
+// applied. Each BP_WEB_SERVER_HEADER_<NAME> variable sets the header of that
+// name, with underscores for dashes, and an empty value removes it. Without a
+// profile, only the overrides are set.
+func SecurityHeaders(environ []string) (string, []Header, error) {
+	profile := "off"
+	overrides := map[string]string{}
+	var added []string
+
+	for _, variable := range environ {
+		name, value, _ := strings.Cut(variable, "=")
+		if name == SecurityHeadersEnv && strings.TrimSpace(value) != "" {
+			profile = strings.TrimSpace(value)
+			continue
+		}
+
+		suffix, ok := strings.CutPrefix(name, HeaderEnvPrefix)
+		if !ok {
+			continue
+		}
+
+		header := textproto.CanonicalMIMEHeaderKey(strings.ReplaceAll(suffix, "_", "-"))
+		if !headerName.MatchString(header) {
+			return "", nil, fmt.Errorf("%s does not name a header", name)
+		}
+
+		if strings.ContainsAny(value, "\"\\$\n") {
+			return "", nil, fmt.Errorf("%s: header values cannot contain quotes, backslashes or $", name)
+		}
+
+		overrides[header] = strings.TrimSpace(value)
+		added = append(added, header)
+	}
+
+	defaults, ok := Profiles[profile]
+	if !ok {
+		return "", nil, fmt.Errorf("unsupported %s profile %q: expected strict, default or off", SecurityHeadersEnv, profile)
+	}
+
+	var headers []Header
+	for _, header := range defaults {
+		if value, ok := overrides[header.Name]; ok {
+			header.Value = value
+		}
+
+		headers = append(headers, header)
+	}
+
+	// Overrides for headers that are not in the profile follow it
+	slices.Sort(added)
+	for _, name := range slices.Compact(added) {
+		if !slices.ContainsFunc(defaults, func(header Header) bool { return header.Name == name }) {
+			headers = append(headers, Header{Name: name, Value: overrides[name]})
+		}
+	}
+
+	return profile, slices.DeleteFunc(headers, func(header Header) bool { return header.Value == "" }), nil
+}
+
+// NginxSecurityHeaders renders the headers as add_header directives. They are
+// sent on error responses too, which is where clickjacking and sniffing
+// protections matter as much as anywhere.
+func NginxSecurityHeaders(generator string, headers []Header) map[serverconf.Context][]byte {
+	buffer := bytes.NewBuffer(nil)
+
+	fmt.Fprintf(buffer, "# Generated by the %s buildpack for %s.\n", generator, SecurityHeadersEnv)
+	for _, header := range headers {
+		fmt.Fprintf(buffer, "add_header %s \"%s\" always;\n", header.Name, header.Value)
+	}
+
+	return map[serverconf.Context][]byte{
+		serverconf.NginxHeaders: buffer.Bytes(),
+	}
+}
+
+// HttpdSecurityHeaders renders the headers as Header directives. A % in a
+// value is escaped, as it would otherwise start a format specifier.
+func HttpdSecurityHeaders(generator string, headers []Header) []byte {
+	buffer := bytes.NewBuffer(nil)
+
+	fmt.Fprintf(buffer, "# Generated by the %s buildpack for %s.\n", generator, SecurityHeadersEnv)
+	buffer.WriteString("<IfModule !mod_headers.c>\n  LoadModule headers_module modules/mod_headers.so\n</IfModule>\n")
+	for _, header := range headers {
+		fmt.Fprintf(buffer, "Header always set %s \"%s\"\n", header.Name, strings.ReplaceAll(header.Value, "%", "%%"))
+	}
+
+	return buffer.Bytes()
+}
@@ -0,0 +1,99 @@
+package webserverconfig_test
+
+import (
+	"testing"
+
+	webserverconfig "github.com/paketo-buildpacks/web-servers/buildpacks/web-server-config"
+	"github.com/paketo-buildpacks/web-servers/internal/serverconf"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testHeaders(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("SecurityHeaders", func() {
+		it("returns the headers of the profile", func() {
+			profile, headers, err := webserverconfig.SecurityHeaders([]string{"BP_WEB_SERVER_SECURITY_HEADERS=default"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(profile).To(Equal("default"))
+			Expect(headers).To(Equal(webserverconfig.Profiles["default"]))
+		})
+
+		it("applies overrides to the profile", func() {
+			profile, headers, err := webserverconfig.SecurityHeaders([]string{
+				"BP_WEB_SERVER_SECURITY_HEADERS=strict",
+				"BP_WEB_SERVER_HEADER_CONTENT_SECURITY_POLICY=default-src 'self' https://api.example.com",
+				"BP_WEB_SERVER_HEADER_PERMISSIONS_POLICY=",
+				"BP_WEB_SERVER_HEADER_X_ROBOTS_TAG=noindex",
+				"SOME_OTHER_VARIABLE=some-value",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(profile).To(Equal("strict"))
+			Expect(headers).To(Equal([]webserverconfig.Header{
+				{Name: "Strict-Transport-Security", Value: "max-age=63072000; includeSubDomains"},
+				{Name: "X-Content-Type-Options", Value: "nosniff"},
+				{Name: "X-Frame-Options", Value: "DENY"},
+				{Name: "Referrer-Policy", Value: "no-referrer"},
+				{Name: "Content-Security-Policy", Value: "default-src 'self' https://api.example.com"},
+				{Name: "Cross-Origin-Opener-Policy", Value: "same-origin"},
+				{Name: "X-Robots-Tag", Value: "noindex"},
+			}))
+		})
+
+		it("sets only the overrides without a profile", func() {
+			profile, headers, err := webserverconfig.SecurityHeaders([]string{"BP_WEB_SERVER_HEADER_X_FRAME_OPTIONS=DENY"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(profile).To(Equal("off"))
+			Expect(headers).To(Equal([]webserverconfig.Header{{Name: "X-Frame-Options", Value: "DENY"}}))
+		})
+
+		context("failure cases", func() {
+			it("rejects unknown profiles", func() {
+				_, _, err := webserverconfig.SecurityHeaders([]string{"BP_WEB_SERVER_SECURITY_HEADERS=paranoid"})
+				Expect(err).To(MatchError(`unsupported BP_WEB_SERVER_SECURITY_HEADERS profile "paranoid": expected strict, default or off`))
+			})
+
+			it("rejects values that cannot be quoted", func() {
+				_, _, err := webserverconfig.SecurityHeaders([]string{`BP_WEB_SERVER_HEADER_X_PRICE=$5`})
+				Expect(err).To(MatchError("BP_WEB_SERVER_HEADER_X_PRICE: header values cannot contain quotes, backslashes or $"))
+			})
+
+			it("rejects invalid header names", func() {
+				_, _, err := webserverconfig.SecurityHeaders([]string{"BP_WEB_SERVER_HEADER_X.FRAME=DENY"})
+				Expect(err).To(MatchError("BP_WEB_SERVER_HEADER_X.FRAME does not name a header"))
+			})
+		})
+	})
+
+	context("NginxSecurityHeaders", func() {
+		it("renders a headers fragment", func() {
+			fragments := webserverconfig.NginxSecurityHeaders("some-org/web-server-config", []webserverconfig.Header{
+				{Name: "X-Frame-Options", Value: "DENY"},
+				{Name: "Content-Security-Policy", Value: "default-src 'self'"},
+			})
+			Expect(fragments).To(HaveLen(1))
+			Expect(string(fragments[serverconf.NginxHeaders])).To(Equal(`# Generated by the some-org/web-server-config buildpack for BP_WEB_SERVER_SECURITY_HEADERS.
+add_header X-Frame-Options "DENY" always;
+add_header Content-Security-Policy "default-src 'self'" always;
+`))
+		})
+	})
+
+	context("HttpdSecurityHeaders", func() {
+		it("renders Header directives", func() {
+			content := webserverconfig.HttpdSecurityHeaders("some-org/web-server-config", []webserverconfig.Header{
+				{Name: "X-Frame-Options", Value: "DENY"},
+				{Name: "X-Progress", Value: "100%"},
+			})
+			Expect(string(content)).To(Equal(`# Generated by the some-org/web-server-config buildpack for BP_WEB_SERVER_SECURITY_HEADERS.
+<IfModule !mod_headers.c>
+  LoadModule headers_module modules/mod_headers.so
+</IfModule>
+Header always set X-Frame-Options "DENY"
+Header always set X-Progress "100%%"
+`))
+		})
+	})
+}
@@ -0,0 +1,78 @@
+package webserverconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/paketo-buildpacks/web-servers/internal/serverconf"
+)
+
+// HealthCheckLabel returns the value of HealthCheckLabelName, which describes
+// the probe so that a platform can configure it without knowing the server.
+func HealthCheckLabel() (string, error) {
+	label, err := json.Marshal(map[string]string{
+		"type":     "http",
+		"path":     HealthCheckPath,
+		"port-env": "PORT",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(label), nil
+}
+
+// NginxHealthCheck renders an exact location that answers HealthCheckPath
+// itself. The server-level rewrites of other fragments, such as the fallbacks
+// of _redirects, run before location matching, so the request skips them.
+func NginxHealthCheck(generator string) map[serverconf.Context][]byte {
+	buffer := bytes.NewBuffer(nil)
+
+	fmt.Fprintf(buffer, "# Generated by the %s buildpack for %s.\n", generator, HealthCheckEnv)
+	fmt.Fprintf(buffer, "if ($uri = \"%s\") { break; }\n", HealthCheckPath)
+	fmt.Fprintf(buffer, "\nlocation = %s {\n", HealthCheckPath)
+	buffer.WriteString("  access_log off;\n")
+	buffer.WriteString("  auth_basic off;\n")
+	buffer.WriteString("  default_type text/plain;\n")
+	buffer.WriteString("  return 200 \"ok\";\n")
+	buffer.WriteString("}\n")
+
+	return map[serverconf.Context][]byte{
+		serverconf.NginxServer: buffer.Bytes(),
+	}
+}
+
+// HttpdHealthCheck renders an alias of HealthCheckPath to HealthCheckFile,
+// which Build writes next to the fragments. The rewrite rule ends rewriting,
+// so the rules of later fragments cannot take the request.
+func HttpdHealthCheck(generator string) []byte {
+	buffer := bytes.NewBuffer(nil)
+
+	fmt.Fprintf(buffer, "# Generated by the %s buildpack for %s.\n", generator, HealthCheckEnv)
+	for _, module := range []string{"rewrite", "alias", "mime", "authz_core"} {
+		fmt.Fprintf(buffer, "<IfModule !mod_%s.c>\n  LoadModule %s_module modules/mod_%s.so\n</IfModule>\n", module, module, module)
+	}
+
+	buffer.WriteString("RewriteEngine On\n")
+	fmt.Fprintf(buffer, "RewriteRule \"^%s$\" - [L]\n", HealthCheckPath)
+	fmt.Fprintf(buffer, "Alias \"%s\" \"${APP_ROOT}/%s/%s\"\n", HealthCheckPath, serverconf.Dir, HealthCheckFile)
+	fmt.Fprintf(buffer, "<Location \"%s\">\n", HealthCheckPath)
+	buffer.WriteString("  ForceType text/plain\n")
+	buffer.WriteString("  Require all granted\n")
+	buffer.WriteString("</Location>\n")
+
+	return buffer.Bytes()
+}
+
+// CaddyHealthCheck renders a response for HealthCheckPath. It matches the
+// path as requested, as rewrites such as try_files run before respond.
+func CaddyHealthCheck(generator string) []byte {
+	buffer := bytes.NewBuffer(nil)
+
+	fmt.Fprintf(buffer, "# Generated by the %s buildpack for %s.\n", generator, HealthCheckEnv)
+	fmt.Fprintf(buffer, "@web_servers_health_check expression `{http.request.orig_uri.path} == '%s'`\n", HealthCheckPath)
+	buffer.WriteString("respond @web_servers_health_check \"ok\" 200\n")
+
+	return buffer.Bytes()
+}
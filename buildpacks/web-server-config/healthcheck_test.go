@@ -0,0 +1,55 @@
+package webserverconfig_test
+
+import (
+	"testing"
+
+	webserverconfig "github.com/paketo-buildpacks/web-servers/buildpacks/web-server-config"
+	"github.com/paketo-buildpacks/web-servers/internal/serverconf"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testHealthCheck(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("NginxHealthCheck", func() {
+		it("answers the health check in an exact location", func() {
+			fragments := webserverconfig.NginxHealthCheck("some-buildpack")
+			Expect(fragments).To(HaveLen(1))
+			Expect(string(fragments[serverconf.NginxServer])).To(Equal(`# Generated by the some-buildpack buildpack for BP_WEB_SERVER_HEALTH_CHECK.
+if ($uri = "/healthz") { break; }
+
+location = /healthz {
+  access_log off;
+  auth_basic off;
+  default_type text/plain;
+  return 200 "ok";
+}
+`))
+		})
+	})
+
+	context("HttpdHealthCheck", func() {
+		it("aliases the health check to the response outside the document root", func() {
+			fragment := string(webserverconfig.HttpdHealthCheck("some-buildpack"))
+			Expect(fragment).To(ContainSubstring("<IfModule !mod_alias.c>\n  LoadModule alias_module modules/mod_alias.so\n</IfModule>\n"))
+			Expect(fragment).To(HaveSuffix(`RewriteEngine On
+RewriteRule "^/healthz$" - [L]
+Alias "/healthz" "${APP_ROOT}/web-servers/healthz"
+<Location "/healthz">
+  ForceType text/plain
+  Require all granted
+</Location>
+`))
+		})
+	})
+
+	context("CaddyHealthCheck", func() {
+		it("responds to the path as requested", func() {
+			Expect(string(webserverconfig.CaddyHealthCheck("some-buildpack"))).To(Equal("# Generated by the some-buildpack buildpack for BP_WEB_SERVER_HEALTH_CHECK.\n" +
+				"@web_servers_health_check expression `{http.request.orig_uri.path} == '/healthz'`\n" +
+				"respond @web_servers_health_check \"ok\" 200\n"))
+		})
+	})
+}
@@ -0,0 +1,22 @@
+package webserverconfig_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitWebServerConfig(t *testing.T) {
+	suite := spec.New("web-server-config", spec.Report(report.Terminal{}), spec.Sequential())
+	suite("Detect", testDetect)
+	suite("Build", testBuild)
+	suite("BasicAuth", testBasicAuth)
+	suite("Headers", testHeaders)
+	suite("HealthCheck", testHealthCheck)
+	suite("Metrics", testMetrics)
+	suite("Precompress", testPrecompress)
+	suite("Proxy", testProxy)
+	suite("TLS", testTLS)
+	suite.Run(t)
+}
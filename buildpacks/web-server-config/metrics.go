@@ -0,0 +1,295 @@
+package webserverconfig
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/paketo-buildpacks/web-servers/internal/serverconf"
+)
+
+// Metric is a sample in the Prometheus text exposition format.
+type Metric struct {
+	Name   string
+	Help   string
+	Type   string
+	Labels map[string]string
+	Value  float64
+}
+
+// StatusURL returns the URL of the status page that the exporter reads for
+// the server.
+func StatusURL(server string) (string, error) {
+	switch server {
+	case "nginx":
+		return fmt.Sprintf("http://%s/stub_status", StatusAddress), nil
+	case "httpd":
+		return fmt.Sprintf("http://%s/server-status?auto", StatusAddress), nil
+	default:
+		return "", fmt.Errorf("metrics are not supported for %s", server)
+	}
+}
+
+// Exporter returns the handler of the MetricsProcess for the server. It reads
+// the status page at statusURL on every scrape; when the page cannot be read,
+// it only reports the server as down.
+func Exporter(server, statusURL string, client *http.Client) (http.Handler, error) {
+	var (
+		prefix string
+		parse  func(io.Reader) ([]Metric, error)
+	)
+	switch server {
+	case "nginx":
+		prefix, parse = "nginx", ParseStubStatus
+	case "httpd":
+		prefix, parse = "apache", ParseServerStatus
+	default:
+		return nil, fmt.Errorf("metrics are not supported for %s", server)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		up := Metric{Name: prefix + "_up", Type: "gauge", Help: fmt.Sprintf("Whether the status of %s could be read", server)}
+
+		metrics, err := scrape(client, statusURL, parse)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read the status of %s: %s\n", server, err)
+		} else {
+			up.Value = 1
+		}
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = WriteMetrics(w, append([]Metric{up}, metrics...))
+	}), nil
+}
+
+func scrape(client *http.Client, statusURL string, parse func(io.Reader) ([]Metric, error)) ([]Metric, error) {
+	resp, err := client.Get(statusURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s from %s", resp.Status, statusURL)
+	}
+
+	return parse(resp.Body)
+}
+
+// Supervise runs the web process and serves handler at /metrics on listener
+// until the web process exits. It forwards the signals it receives to the web
+// process and returns the exit code of the web process, so the
+// MetricsProcess stops with the web server it reports on.
+func Supervise(web *exec.Cmd, listener net.Listener, handler http.Handler, signals <-chan os.Signal) (int, error) {
+	err := web.Start()
+	if err != nil {
+		return 0, fmt.Errorf("failed to start the web process: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", handler)
+
+	server := &http.Server{Handler: mux}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- web.Wait()
+	}()
+
+	for {
+		select {
+		case sig := <-signals:
+			_ = web.Process.Signal(sig)
+		case err := <-done:
+			return exitCode(err)
+		}
+	}
+}
+
+func exitCode(err error) (int, error) {
+	if err == nil {
+		return 0, nil
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return 0, err
+	}
+
+	status, ok := exitErr.Sys().(syscall.WaitStatus)
+	if ok && status.Signaled() {
+		return 128 + int(status.Signal()), nil
+	}
+
+	return exitErr.ExitCode(), nil
+}
+
+// NginxStatus renders a server that only listens on StatusAddress and serves
+// stub_status, so the page is not reachable from outside the container.
+func NginxStatus(generator string) map[serverconf.Context][]byte {
+	buffer := bytes.NewBuffer(nil)
+
+	fmt.Fprintf(buffer, "# Generated by the %s buildpack for %s.\n", generator, MetricsEnv)
+	buffer.WriteString("server {\n")
+	fmt.Fprintf(buffer, "  listen %s;\n", StatusAddress)
+	buffer.WriteString("  access_log off;\n")
+	buffer.WriteString("\n  location = /stub_status {\n    stub_status;\n  }\n")
+	buffer.WriteString("}\n")
+
+	return map[serverconf.Context][]byte{
+		serverconf.NginxHTTP: buffer.Bytes(),
+	}
+}
+
+// HttpdStatus renders a virtual host that only listens on StatusAddress and
+// serves mod_status. Sections of a virtual host apply after those of the main
+// server, so basic authentication does not cover it, and TLS is turned off
+// when it is on for the main server.
+func HttpdStatus(generator string) []byte {
+	buffer := bytes.NewBuffer(nil)
+
+	fmt.Fprintf(buffer, "# Generated by the %s buildpack for %s.\n", generator, MetricsEnv)
+	for _, module := range []string{"status", "authz_core", "authz_host"} {
+		fmt.Fprintf(buffer, "<IfModule !mod_%s.c>\n  LoadModule %s_module modules/mod_%s.so\n</IfModule>\n", module, module, module)
+	}
+
+	fmt.Fprintf(buffer, "Listen %s\n", StatusAddress)
+	fmt.Fprintf(buffer, "<VirtualHost %s>\n", StatusAddress)
+	buffer.WriteString("  <IfModule mod_ssl.c>\n    SSLEngine off\n  </IfModule>\n")
+	buffer.WriteString("  <Location \"/server-status\">\n")
+	buffer.WriteString("    SetHandler server-status\n")
+	buffer.WriteString("    Require local\n")
+	buffer.WriteString("  </Location>\n")
+	buffer.WriteString("</VirtualHost>\n")
+
+	return buffer.Bytes()
+}
+
+var stubStatus = regexp.MustCompile(`(?s)Active connections:\s*(\d+).*?\n\s*(\d+)\s+(\d+)\s+(\d+)\s*\nReading:\s*(\d+)\s+Writing:\s*(\d+)\s+Waiting:\s*(\d+)`)
+
+// ParseStubStatus reads the page of the NGINX stub_status module, naming the
+// metrics as the NGINX Prometheus exporter does.
+func ParseStubStatus(reader io.Reader) ([]Metric, error) {
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	match := stubStatus.FindStringSubmatch(string(content))
+	if match == nil {
+		return nil, fmt.Errorf("failed to parse stub_status: unexpected content %q", content)
+	}
+
+	values := make([]float64, len(match)-1)
+	for i, value := range match[1:] {
+		values[i], err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return []Metric{
+		{Name: "nginx_connections_active", Type: "gauge", Help: "Active client connections", Value: values[0]},
+		{Name: "nginx_connections_accepted", Type: "counter", Help: "Accepted client connections", Value: values[1]},
+		{Name: "nginx_connections_handled", Type: "counter", Help: "Handled client connections", Value: values[2]},
+		{Name: "nginx_http_requests_total", Type: "counter", Help: "Total http requests", Value: values[3]},
+		{Name: "nginx_connections_reading", Type: "gauge", Help: "Connections where NGINX is reading the request header", Value: values[4]},
+		{Name: "nginx_connections_writing", Type: "gauge", Help: "Connections where NGINX is writing the response back to the client", Value: values[5]},
+		{Name: "nginx_connections_waiting", Type: "gauge", Help: "Idle client connections", Value: values[6]},
+	}, nil
+}
+
+// serverStatus maps the fields of the machine-readable mod_status page to
+// the metrics they are reported as, named as the Apache Prometheus exporter
+// does.
+var serverStatus = map[string]Metric{
+	"Total Accesses": {Name: "apache_accesses_total", Type: "counter", Help: "Current total apache accesses"},
+	"Total kBytes":   {Name: "apache_sent_kilobytes_total", Type: "counter", Help: "Current total kbytes sent"},
+	"Uptime":         {Name: "apache_uptime_seconds_total", Type: "counter", Help: "Current uptime in seconds"},
+	"BusyWorkers":    {Name: "apache_workers", Type: "gauge", Help: "Apache worker statuses", Labels: map[string]string{"state": "busy"}},
+	"IdleWorkers":    {Name: "apache_workers", Type: "gauge", Help: "Apache worker statuses", Labels: map[string]string{"state": "idle"}},
+	"ConnsTotal":     {Name: "apache_connections", Type: "gauge", Help: "Apache connection statuses", Labels: map[string]string{"state": "total"}},
+}
+
+// ParseServerStatus reads the machine-readable page of HTTPD's mod_status,
+// the server-status?auto endpoint. Fields without a metric are skipped.
+func ParseServerStatus(reader io.Reader) ([]Metric, error) {
+	var metrics []Metric
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+
+		metric, ok := serverStatus[key]
+		if !ok {
+			continue
+		}
+
+		var err error
+		metric.Value, err = strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse server-status field %q: %w", key, err)
+		}
+
+		metrics = append(metrics, metric)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if !slices.ContainsFunc(metrics, func(metric Metric) bool { return metric.Name == "apache_accesses_total" }) {
+		return nil, fmt.Errorf("failed to parse server-status: no Total Accesses field")
+	}
+
+	// Keep the samples of a metric together
+	slices.SortStableFunc(metrics, func(a, b Metric) int { return strings.Compare(a.Name, b.Name) })
+
+	return metrics, nil
+}
+
+// WriteMetrics writes the metrics in the Prometheus text exposition format.
+// Samples of the same metric must be adjacent.
+func WriteMetrics(writer io.Writer, metrics []Metric) error {
+	buffer := bytes.NewBuffer(nil)
+
+	for i, metric := range metrics {
+		if i == 0 || metrics[i-1].Name != metric.Name {
+			fmt.Fprintf(buffer, "# HELP %s %s\n", metric.Name, metric.Help)
+			fmt.Fprintf(buffer, "# TYPE %s %s\n", metric.Name, metric.Type)
+		}
+
+		var labels []string
+		for _, name := range slices.Sorted(maps.Keys(metric.Labels)) {
+			labels = append(labels, fmt.Sprintf("%s=%q", name, metric.Labels[name]))
+		}
+
+		buffer.WriteString(metric.Name)
+		if len(labels) > 0 {
+			fmt.Fprintf(buffer, "{%s}", strings.Join(labels, ","))
+		}
+		fmt.Fprintf(buffer, " %s\n", strconv.FormatFloat(metric.Value, 'f', -1, 64))
+	}
+
+	_, err := writer.Write(buffer.Bytes())
+	return err
+}
@@ -0,0 +1,65 @@
+// Command metrics is the launch process contributed by the web-server-config
+// buildpack when BP_WEB_SERVER_METRICS is set. It runs the web process as its
+// child, so the web server and its metrics share a container, and serves the
+// status of the server named by its argument as Prometheus metrics at
+// /metrics until the web server exits.
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	webserverconfig "github.com/paketo-buildpacks/web-servers/buildpacks/web-server-config"
+)
+
+func main() {
+	code, err := run(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	os.Exit(code)
+}
+
+func run(args []string) (int, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf("usage: %s <server>", webserverconfig.MetricsProcess)
+	}
+
+	statusURL, err := webserverconfig.StatusURL(args[0])
+	if err != nil {
+		return 0, err
+	}
+
+	exporter, err := webserverconfig.Exporter(args[0], statusURL, &http.Client{Timeout: 5 * time.Second})
+	if err != nil {
+		return 0, err
+	}
+
+	port := os.Getenv(webserverconfig.MetricsPortEnv)
+	if port == "" {
+		port = webserverconfig.DefaultMetricsPort
+	}
+
+	listener, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return 0, err
+	}
+
+	web := exec.Command(webserverconfig.WebProcess)
+	web.Stdin, web.Stdout, web.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+
+	fmt.Printf("Serving the metrics of %s on :%s/metrics\n", args[0], port)
+
+	return webserverconfig.Supervise(web, listener, exporter, signals)
+}
@@ -0,0 +1,266 @@
+package webserverconfig_test
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	webserverconfig "github.com/paketo-buildpacks/web-servers/buildpacks/web-server-config"
+	"github.com/paketo-buildpacks/web-servers/internal/serverconf"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testMetrics(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+	)
+
+	context("NginxStatus", func() {
+		it("serves stub_status on the loopback address", func() {
+			fragments := webserverconfig.NginxStatus("some-buildpack")
+			Expect(fragments).To(HaveLen(1))
+			Expect(string(fragments[serverconf.NginxHTTP])).To(Equal(`# Generated by the some-buildpack buildpack for BP_WEB_SERVER_METRICS.
+server {
+  listen 127.0.0.1:18081;
+  access_log off;
+
+  location = /stub_status {
+    stub_status;
+  }
+}
+`))
+		})
+	})
+
+	context("HttpdStatus", func() {
+		it("serves mod_status in a virtual host on the loopback address", func() {
+			fragment := string(webserverconfig.HttpdStatus("some-buildpack"))
+			Expect(fragment).To(ContainSubstring("<IfModule !mod_status.c>\n  LoadModule status_module modules/mod_status.so\n</IfModule>\n"))
+			Expect(fragment).To(HaveSuffix(`Listen 127.0.0.1:18081
+<VirtualHost 127.0.0.1:18081>
+  <IfModule mod_ssl.c>
+    SSLEngine off
+  </IfModule>
+  <Location "/server-status">
+    SetHandler server-status
+    Require local
+  </Location>
+</VirtualHost>
+`))
+		})
+	})
+
+	context("ParseStubStatus", func() {
+		it("reads the connection and request counters", func() {
+			metrics, err := webserverconfig.ParseStubStatus(strings.NewReader("Active connections: 2 \nserver accepts handled requests\n 16 16 31 \nReading: 0 Writing: 1 Waiting: 1 \n"))
+			Expect(err).NotTo(HaveOccurred())
+
+			values := map[string]float64{}
+			for _, metric := range metrics {
+				values[metric.Name] = metric.Value
+			}
+			Expect(values).To(Equal(map[string]float64{
+				"nginx_connections_active":   2,
+				"nginx_connections_accepted": 16,
+				"nginx_connections_handled":  16,
+				"nginx_http_requests_total":  31,
+				"nginx_connections_reading":  0,
+				"nginx_connections_writing":  1,
+				"nginx_connections_waiting":  1,
+			}))
+		})
+
+		context("when the page is not a stub_status page", func() {
+			it("returns an error", func() {
+				_, err := webserverconfig.ParseStubStatus(strings.NewReader("<html></html>"))
+				Expect(err).To(MatchError(ContainSubstring("failed to parse stub_status")))
+			})
+		})
+	})
+
+	context("ParseServerStatus", func() {
+		it("reads the counters of the machine-readable page", func() {
+			metrics, err := webserverconfig.ParseServerStatus(strings.NewReader(`localhost
+ServerVersion: Apache/2.4.62 (Unix)
+Total Accesses: 42
+Total kBytes: 128
+Uptime: 300
+BusyWorkers: 1
+IdleWorkers: 74
+ConnsTotal: 1
+Scoreboard: _W___
+`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(metrics).To(Equal([]webserverconfig.Metric{
+				{Name: "apache_accesses_total", Type: "counter", Help: "Current total apache accesses", Value: 42},
+				{Name: "apache_connections", Type: "gauge", Help: "Apache connection statuses", Labels: map[string]string{"state": "total"}, Value: 1},
+				{Name: "apache_sent_kilobytes_total", Type: "counter", Help: "Current total kbytes sent", Value: 128},
+				{Name: "apache_uptime_seconds_total", Type: "counter", Help: "Current uptime in seconds", Value: 300},
+				{Name: "apache_workers", Type: "gauge", Help: "Apache worker statuses", Labels: map[string]string{"state": "busy"}, Value: 1},
+				{Name: "apache_workers", Type: "gauge", Help: "Apache worker statuses", Labels: map[string]string{"state": "idle"}, Value: 74},
+			}))
+		})
+
+		context("when the page has no Total Accesses", func() {
+			it("returns an error", func() {
+				_, err := webserverconfig.ParseServerStatus(strings.NewReader("Uptime: 300\n"))
+				Expect(err).To(MatchError("failed to parse server-status: no Total Accesses field"))
+			})
+		})
+
+		context("when a field is not a number", func() {
+			it("returns an error", func() {
+				_, err := webserverconfig.ParseServerStatus(strings.NewReader("Total Accesses: many\n"))
+				Expect(err).To(MatchError(ContainSubstring(`failed to parse server-status field "Total Accesses"`)))
+			})
+		})
+	})
+
+	context("WriteMetrics", func() {
+		it("writes the text exposition format", func() {
+			buffer := &strings.Builder{}
+			Expect(webserverconfig.WriteMetrics(buffer, []webserverconfig.Metric{
+				{Name: "apache_accesses_total", Type: "counter", Help: "Current total apache accesses", Value: 42},
+				{Name: "apache_workers", Type: "gauge", Help: "Apache worker statuses", Labels: map[string]string{"state": "busy"}, Value: 1},
+				{Name: "apache_workers", Type: "gauge", Help: "Apache worker statuses", Labels: map[string]string{"state": "idle"}, Value: 74},
+			})).To(Succeed())
+
+			Expect(buffer.String()).To(Equal(`# HELP apache_accesses_total Current total apache accesses
+# TYPE apache_accesses_total counter
+apache_accesses_total 42
+# HELP apache_workers Apache worker statuses
+# TYPE apache_workers gauge
+apache_workers{state="busy"} 1
+apache_workers{state="idle"} 74
+`))
+		})
+	})
+
+	context("Exporter", func() {
+		var status *httptest.Server
+
+		it.Before(func() {
+			status = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				_, _ = io.WriteString(w, "Active connections: 1 \nserver accepts handled requests\n 3 3 7 \nReading: 0 Writing: 1 Waiting: 0 \n")
+			}))
+		})
+
+		it.After(func() {
+			status.Close()
+		})
+
+		it("serves the metrics of the status page", func() {
+			exporter, err := webserverconfig.Exporter("nginx", status.URL, status.Client())
+			Expect(err).NotTo(HaveOccurred())
+
+			recorder := httptest.NewRecorder()
+			exporter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Body.String()).To(HavePrefix("# HELP nginx_up Whether the status of nginx could be read\n# TYPE nginx_up gauge\nnginx_up 1\n"))
+			Expect(recorder.Body.String()).To(ContainSubstring("\nnginx_http_requests_total 7\n"))
+		})
+
+		context("when the status page cannot be read", func() {
+			it.Before(func() {
+				status.Close()
+			})
+
+			it("reports the server as down", func() {
+				exporter, err := webserverconfig.Exporter("httpd", status.URL, status.Client())
+				Expect(err).NotTo(HaveOccurred())
+
+				recorder := httptest.NewRecorder()
+				exporter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+				Expect(recorder.Code).To(Equal(http.StatusOK))
+				Expect(recorder.Body.String()).To(Equal("# HELP apache_up Whether the status of httpd could be read\n# TYPE apache_up gauge\napache_up 0\n"))
+			})
+		})
+
+		context("when the server is not supported", func() {
+			it("returns an error", func() {
+				_, err := webserverconfig.Exporter("caddy", status.URL, status.Client())
+				Expect(err).To(MatchError("metrics are not supported for caddy"))
+			})
+		})
+	})
+
+	context("Supervise", func() {
+		var (
+			listener net.Listener
+			handler  http.Handler
+			signals  chan os.Signal
+		)
+
+		it.Before(func() {
+			var err error
+			listener, err = net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+
+			handler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				_, _ = io.WriteString(w, "nginx_up 1\n")
+			})
+
+			signals = make(chan os.Signal, 1)
+		})
+
+		it("returns the exit code of the web process", func() {
+			code, err := webserverconfig.Supervise(exec.Command("sh", "-c", "exit 3"), listener, handler, signals)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(code).To(Equal(3))
+		})
+
+		it("serves the metrics while the web process runs and forwards signals to it", func() {
+			type result struct {
+				code int
+				err  error
+			}
+			done := make(chan result, 1)
+			go func() {
+				code, err := webserverconfig.Supervise(exec.Command("sleep", "30"), listener, handler, signals)
+				done <- result{code, err}
+			}()
+
+			var body string
+			Eventually(func() error {
+				resp, err := http.Get("http://" + listener.Addr().String() + "/metrics")
+				if err != nil {
+					return err
+				}
+				defer resp.Body.Close()
+
+				content, err := io.ReadAll(resp.Body)
+				body = string(content)
+				return err
+			}).Should(Succeed())
+			Expect(body).To(Equal("nginx_up 1\n"))
+
+			signals <- syscall.SIGTERM
+
+			var r result
+			Eventually(done, 5*time.Second).Should(Receive(&r))
+			Expect(r.err).NotTo(HaveOccurred())
+			Expect(r.code).To(Equal(143))
+		})
+
+		context("failure cases", func() {
+			context("when the web process cannot be started", func() {
+				it("returns an error", func() {
+					_, err := webserverconfig.Supervise(exec.Command("/no/such/web"), listener, handler, signals)
+					Expect(err).To(MatchError(ContainSubstring("failed to start the web process")))
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,235 @@
+package webserverconfig
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/paketo-buildpacks/web-servers/internal/serverconf"
+)
+
+// Encoding is a content coding that assets can be precompressed with.
+type Encoding struct {
+	// Name is the token used in BP_WEB_SERVER_PRECOMPRESS and in the
+	// Content-Encoding header.
+	Name string
+
+	// Extension is appended to the asset name to form its compressed sibling.
+	Extension string
+}
+
+var (
+	Brotli = Encoding{Name: "br", Extension: ".br"}
+	Gzip   = Encoding{Name: "gzip", Extension: ".gz"}
+)
+
+// ParseEncodings reads a comma- or whitespace-separated list of encodings,
+// such as "gzip,br". The result is in order of preference, brotli first,
+// whatever the order of the list.
+func ParseEncodings(value string) ([]Encoding, error) {
+	names := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+
+	for _, name := range names {
+		if name != Brotli.Name && name != Gzip.Name {
+			return nil, fmt.Errorf("unsupported %s encoding %q: expected gzip or br", PrecompressEnv, name)
+		}
+	}
+
+	var encodings []Encoding
+	for _, encoding := range []Encoding{Brotli, Gzip} {
+		if slices.Contains(names, encoding.Name) {
+			encodings = append(encodings, encoding)
+		}
+	}
+
+	return encodings, nil
+}
+
+// Compress returns content compressed at the highest level of the encoding.
+// Gzip output carries no timestamp, so the result is reproducible.
+func (e Encoding) Compress(content []byte) ([]byte, error) {
+	buffer := bytes.NewBuffer(nil)
+
+	var writer io.WriteCloser
+	switch e {
+	case Brotli:
+		writer = brotli.NewWriterLevel(buffer, brotli.BestCompression)
+	case Gzip:
+		var err error
+		writer, err = gzip.NewWriterLevel(buffer, gzip.BestCompression)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported encoding %q", e.Name)
+	}
+
+	_, err := writer.Write(content)
+	if err != nil {
+		return nil, err
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// Precompress writes a compressed sibling of every text asset below root for
+// each encoding, returning the number written per encoding name. Siblings
+// that already exist, such as those emitted by the frontend build, are kept,
+// and none is written when compression would not make the asset smaller.
+func Precompress(root string, encodings []Encoding) (map[string]int, error) {
+	written := map[string]int{}
+
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		if _, ok := TextAssets[strings.ToLower(filepath.Ext(path))]; !ok {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		for _, encoding := range encodings {
+			_, err := os.Lstat(path + encoding.Extension)
+			if err == nil {
+				continue
+			}
+
+			if !os.IsNotExist(err) {
+				return err
+			}
+
+			compressed, err := encoding.Compress(content)
+			if err != nil {
+				return fmt.Errorf("failed to compress %s: %w", path, err)
+			}
+
+			if len(compressed) >= len(content) {
+				continue
+			}
+
+			err = os.WriteFile(path+encoding.Extension, compressed, 0644)
+			if err != nil {
+				return fmt.Errorf("failed to write %s: %w", path+encoding.Extension, err)
+			}
+
+			written[encoding.Name]++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return written, nil
+}
+
+// NginxPrecompress renders the server fragment that serves the siblings.
+// gzip_static covers gzip. NGINX has no equivalent for brotli without a
+// third-party module, so requests that accept it are rewritten to the .br
+// sibling when one exists, and a location per media type restores the type
+// of the original asset and sets Content-Encoding.
+func NginxPrecompress(generator string, encodings []Encoding) map[serverconf.Context][]byte {
+	buffer := bytes.NewBuffer(nil)
+
+	fmt.Fprintf(buffer, "# Generated by the %s buildpack for %s.\n", generator, PrecompressEnv)
+
+	if slices.Contains(encodings, Gzip) {
+		buffer.WriteString("gzip_static on;\ngzip_vary on;\n")
+	}
+
+	if slices.Contains(encodings, Brotli) {
+		buffer.WriteString(`
+set $precompressed_br "";
+if ($http_accept_encoding ~* "\bbr\b") { set $precompressed_br "A"; }
+if (-f $request_filename.br) { set $precompressed_br "${precompressed_br}B"; }
+if ($precompressed_br = "AB") { rewrite ^ $uri.br last; }
+`)
+
+		for _, extension := range slices.Sorted(maps.Keys(TextAssets)) {
+			fmt.Fprintf(buffer, `
+location ~* \%s\.br$ {
+  types { }
+  default_type %s;
+  gzip off;
+  add_header Content-Encoding br;
+  add_header Vary Accept-Encoding;
+  include %s;
+}
+`, extension, TextAssets[extension], serverconf.NginxHeaders.Include())
+		}
+	}
+
+	return map[serverconf.Context][]byte{
+		serverconf.NginxServer: buffer.Bytes(),
+	}
+}
+
+// HttpdPrecompress renders the fragment that serves the siblings. Requests
+// that accept an encoding are rewritten to its sibling when one exists, and
+// mod_mime derives the media type from the original extension and
+// Content-Encoding from the sibling's.
+func HttpdPrecompress(generator string, encodings []Encoding) []byte {
+	buffer := bytes.NewBuffer(nil)
+
+	fmt.Fprintf(buffer, "# Generated by the %s buildpack for %s.\n", generator, PrecompressEnv)
+	buffer.WriteString("<IfModule !mod_rewrite.c>\n  LoadModule rewrite_module modules/mod_rewrite.so\n</IfModule>\n")
+	buffer.WriteString("<IfModule !mod_headers.c>\n  LoadModule headers_module modules/mod_headers.so\n</IfModule>\n")
+	buffer.WriteString("RewriteEngine On\n")
+
+	var extensions, suffixes []string
+	for _, encoding := range encodings {
+		extensions = append(extensions, encoding.Extension)
+		suffixes = append(suffixes, strings.TrimPrefix(encoding.Extension, "."))
+
+		fmt.Fprintf(buffer, "\nRewriteCond \"%%{HTTP:Accept-Encoding}\" \"\\b%s\\b\"\n", encoding.Name)
+		fmt.Fprintf(buffer, "RewriteCond \"%%{DOCUMENT_ROOT}%%{REQUEST_URI}%s\" -f\n", encoding.Extension)
+		fmt.Fprintf(buffer, "RewriteRule \"^(.*)$\" \"$1%s\" [PT,L]\n", encoding.Extension)
+	}
+
+	// Stock configurations map .gz to application/x-gzip, which would replace
+	// the media type of the original asset. Other .gz and .br files the app
+	// serves are left alone.
+	fmt.Fprintf(buffer, "\n<FilesMatch \"\\.(%s)\\.(%s)$\">\n", strings.Join(assetSuffixes(), "|"), strings.Join(suffixes, "|"))
+	fmt.Fprintf(buffer, "  RemoveType %s\n", strings.Join(extensions, " "))
+	for _, encoding := range encodings {
+		fmt.Fprintf(buffer, "  AddEncoding %s %s\n", encoding.Name, encoding.Extension)
+	}
+	buffer.WriteString("  Header append Vary Accept-Encoding\n</FilesMatch>\n")
+
+	return buffer.Bytes()
+}
+
+// assetSuffixes returns the extensions in TextAssets without their dot.
+func assetSuffixes() []string {
+	var suffixes []string
+	for _, extension := range slices.Sorted(maps.Keys(TextAssets)) {
+		suffixes = append(suffixes, strings.TrimPrefix(extension, "."))
+	}
+
+	return suffixes
+}
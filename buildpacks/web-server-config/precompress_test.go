@@ -0,0 +1,154 @@
+package webserverconfig_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	webserverconfig "github.com/paketo-buildpacks/web-servers/buildpacks/web-server-config"
+	"github.com/paketo-buildpacks/web-servers/internal/serverconf"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testPrecompress(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("ParseEncodings", func() {
+		it("returns the encodings in order of preference", func() {
+			encodings, err := webserverconfig.ParseEncodings("gzip, br")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(encodings).To(Equal([]webserverconfig.Encoding{webserverconfig.Brotli, webserverconfig.Gzip}))
+		})
+
+		context("failure cases", func() {
+			it("rejects unknown encodings", func() {
+				_, err := webserverconfig.ParseEncodings("gzip,zstd")
+				Expect(err).To(MatchError(`unsupported BP_WEB_SERVER_PRECOMPRESS encoding "zstd": expected gzip or br`))
+			})
+		})
+	})
+
+	context("Precompress", func() {
+		var (
+			root    string
+			content string
+		)
+
+		it.Before(func() {
+			root = t.TempDir()
+			content = strings.Repeat("body { color: red; }\n", 100)
+
+			Expect(os.MkdirAll(filepath.Join(root, "static", "css"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(root, "static", "css", "main.css"), []byte(content), 0600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(root, "logo.png"), []byte(content), 0600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(root, "tiny.txt"), []byte("a"), 0600)).To(Succeed())
+		})
+
+		it("writes compressed siblings of text assets", func() {
+			written, err := webserverconfig.Precompress(root, []webserverconfig.Encoding{webserverconfig.Brotli, webserverconfig.Gzip})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(written).To(Equal(map[string]int{"br": 1, "gzip": 1}))
+
+			compressed, err := os.ReadFile(filepath.Join(root, "static", "css", "main.css.br"))
+			Expect(err).NotTo(HaveOccurred())
+			decompressed, err := io.ReadAll(brotli.NewReader(bytes.NewReader(compressed)))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(decompressed)).To(Equal(content))
+
+			compressed, err = os.ReadFile(filepath.Join(root, "static", "css", "main.css.gz"))
+			Expect(err).NotTo(HaveOccurred())
+			reader, err := gzip.NewReader(bytes.NewReader(compressed))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reader.ModTime.IsZero()).To(BeTrue())
+			decompressed, err = io.ReadAll(reader)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(decompressed)).To(Equal(content))
+
+			Expect(filepath.Join(root, "logo.png.br")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(root, "tiny.txt.br")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(root, "tiny.txt.gz")).NotTo(BeAnExistingFile())
+		})
+
+		context("when a sibling already exists", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(root, "static", "css", "main.css.gz"), []byte("some-gzip"), 0600)).To(Succeed())
+			})
+
+			it("keeps it", func() {
+				written, err := webserverconfig.Precompress(root, []webserverconfig.Encoding{webserverconfig.Gzip})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(written).To(BeEmpty())
+
+				compressed, err := os.ReadFile(filepath.Join(root, "static", "css", "main.css.gz"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(compressed)).To(Equal("some-gzip"))
+			})
+		})
+	})
+
+	context("NginxPrecompress", func() {
+		it("serves gzip siblings with gzip_static", func() {
+			fragments := webserverconfig.NginxPrecompress("some-org/web-server-config", []webserverconfig.Encoding{webserverconfig.Gzip})
+			Expect(fragments).To(HaveLen(1))
+			Expect(string(fragments[serverconf.NginxServer])).To(Equal(`# Generated by the some-org/web-server-config buildpack for BP_WEB_SERVER_PRECOMPRESS.
+gzip_static on;
+gzip_vary on;
+`))
+		})
+
+		it("rewrites requests that accept brotli to the br sibling", func() {
+			fragments := webserverconfig.NginxPrecompress("some-org/web-server-config", []webserverconfig.Encoding{webserverconfig.Brotli})
+
+			fragment := string(fragments[serverconf.NginxServer])
+			Expect(fragment).NotTo(ContainSubstring("gzip_static"))
+			Expect(fragment).To(ContainSubstring(`if ($precompressed_br = "AB") { rewrite ^ $uri.br last; }`))
+			Expect(fragment).To(ContainSubstring(`
+location ~* \.css\.br$ {
+  types { }
+  default_type text/css;
+  gzip off;
+  add_header Content-Encoding br;
+  add_header Vary Accept-Encoding;
+  include web-servers/nginx/headers/*.conf;
+}
+`))
+		})
+	})
+
+	context("HttpdPrecompress", func() {
+		it("rewrites requests to the sibling of the preferred encoding", func() {
+			content := webserverconfig.HttpdPrecompress("some-org/web-server-config", []webserverconfig.Encoding{webserverconfig.Brotli, webserverconfig.Gzip})
+			Expect(string(content)).To(Equal(`# Generated by the some-org/web-server-config buildpack for BP_WEB_SERVER_PRECOMPRESS.
+<IfModule !mod_rewrite.c>
+  LoadModule rewrite_module modules/mod_rewrite.so
+</IfModule>
+<IfModule !mod_headers.c>
+  LoadModule headers_module modules/mod_headers.so
+</IfModule>
+RewriteEngine On
+
+RewriteCond "%{HTTP:Accept-Encoding}" "\bbr\b"
+RewriteCond "%{DOCUMENT_ROOT}%{REQUEST_URI}.br" -f
+RewriteRule "^(.*)$" "$1.br" [PT,L]
+
+RewriteCond "%{HTTP:Accept-Encoding}" "\bgzip\b"
+RewriteCond "%{DOCUMENT_ROOT}%{REQUEST_URI}.gz" -f
+RewriteRule "^(.*)$" "$1.gz" [PT,L]
+
+<FilesMatch "\.(css|htm|html|js|json|map|mjs|svg|txt|wasm|webmanifest|xml)\.(br|gz)$">
+  RemoveType .br .gz
+  AddEncoding br .br
+  AddEncoding gzip .gz
+  Header append Vary Accept-Encoding
+</FilesMatch>
+`))
+		})
+	})
+}
@@ -0,0 +1,181 @@
+package webserverconfig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/paketo-buildpacks/web-servers/internal/serverconf"
+)
+
+// Route forwards the requests below Path to the backend at URL.
+type Route struct {
+	Path string `toml:"path"`
+	URL  string `toml:"url"`
+}
+
+// Config is the content of ConfigFile.
+type Config struct {
+	Proxy []Route `toml:"proxy"`
+}
+
+var routePath = regexp.MustCompile(`^(/[A-Za-z0-9._~-]+)+$`)
+
+// Routes returns the proxy routes declared in the ConfigFile of workingDir,
+// if there is one, and in value, a comma- or whitespace-separated list of
+// path=url pairs such as "/api=http://backend:8080". A route in value
+// replaces one in the file with the same path. The result is ordered longest
+// path first, so that the most specific route matches first.
+func Routes(workingDir, value string) ([]Route, error) {
+	var config Config
+	metadata, err := toml.DecodeFile(filepath.Join(workingDir, ConfigFile), &config)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to parse %s: %w", ConfigFile, err)
+	}
+
+	if undecoded := metadata.Undecoded(); len(undecoded) > 0 {
+		return nil, fmt.Errorf("failed to parse %s: unknown key %s", ConfigFile, undecoded[0])
+	}
+
+	routes := map[string]Route{}
+	for _, route := range config.Proxy {
+		routes[strings.TrimSuffix(route.Path, "/")] = route
+	}
+
+	for _, pair := range strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	}) {
+		path, target, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid %s route %q: expected path=url", ProxyEnv, pair)
+		}
+
+		routes[strings.TrimSuffix(path, "/")] = Route{Path: path, URL: target}
+	}
+
+	var result []Route
+	for path, route := range routes {
+		if !routePath.MatchString(path) {
+			return nil, fmt.Errorf("invalid proxy path %q: expected an absolute path other than /", route.Path)
+		}
+
+		backend, err := url.Parse(route.URL)
+		if err != nil || (backend.Scheme != "http" && backend.Scheme != "https") || backend.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q for %s: expected an http or https URL", route.URL, path)
+		}
+
+		if backend.User != nil || backend.RawQuery != "" || backend.Fragment != "" {
+			return nil, fmt.Errorf("invalid proxy URL %q for %s: cannot contain credentials, a query or a fragment", route.URL, path)
+		}
+
+		if strings.ContainsAny(route.URL, "\"'\\$;{} ") {
+			return nil, fmt.Errorf("invalid proxy URL %q for %s: cannot contain quotes, backslashes, $, ;, braces or spaces", route.URL, path)
+		}
+
+		result = append(result, Route{Path: path, URL: route.URL})
+	}
+
+	slices.SortFunc(result, func(a, b Route) int {
+		if len(a.Path) != len(b.Path) {
+			return len(b.Path) - len(a.Path)
+		}
+
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return result, nil
+}
+
+// upstream returns the origin of the backend and the path that the route
+// path is mapped to, without a trailing slash. A URL without a path keeps the
+// request path unchanged, and a URL with one replaces the route path with it.
+func (r Route) upstream() (string, string) {
+	backend, _ := url.Parse(r.URL)
+
+	path := strings.TrimSuffix(backend.Path, "/")
+	if backend.Path == "" {
+		path = r.Path
+	}
+
+	return fmt.Sprintf("%s://%s", backend.Scheme, backend.Host), path
+}
+
+// NginxProxy renders a pair of locations per route, one for the path itself
+// and a ^~ prefix location below it, so that regular expression locations do
+// not take its requests. The server-level rewrites of other fragments, such
+// as the fallbacks of _redirects, run before location matching, so requests
+// for the routes skip them.
+func NginxProxy(generator string, routes []Route) map[serverconf.Context][]byte {
+	buffer := bytes.NewBuffer(nil)
+
+	fmt.Fprintf(buffer, "# Generated by the %s buildpack for %s and %s.\n", generator, ProxyEnv, ConfigFile)
+
+	for _, route := range routes {
+		fmt.Fprintf(buffer, "if ($uri ~ \"^%s(/|$)\") { break; }\n", regexp.QuoteMeta(route.Path))
+	}
+
+	for _, route := range routes {
+		origin, path := route.upstream()
+
+		exact := origin + path
+		if path == "" {
+			exact += "/"
+		}
+
+		for _, location := range []struct{ match, target string }{
+			{match: "= " + route.Path, target: exact},
+			{match: "^~ " + route.Path + "/", target: origin + path + "/"},
+		} {
+			fmt.Fprintf(buffer, "\nlocation %s {\n", location.match)
+			fmt.Fprintf(buffer, "  proxy_pass %s;\n", location.target)
+			buffer.WriteString("  proxy_http_version 1.1;\n")
+			buffer.WriteString("  proxy_set_header X-Forwarded-For $proxy_add_x_forwarded_for;\n")
+			buffer.WriteString("  proxy_set_header X-Forwarded-Host $host;\n")
+			buffer.WriteString("  proxy_set_header X-Forwarded-Proto $scheme;\n")
+			if strings.HasPrefix(origin, "https:") {
+				buffer.WriteString("  proxy_ssl_server_name on;\n")
+			}
+			buffer.WriteString("}\n")
+		}
+	}
+
+	return map[serverconf.Context][]byte{
+		serverconf.NginxServer: buffer.Bytes(),
+	}
+}
+
+// HttpdProxy renders a proxying rewrite rule per route. mod_rewrite runs
+// before ProxyPass, so rules rather than ProxyPass directives keep the
+// fallbacks of _redirects from taking the requests; the fragment is included
+// ahead of theirs and [L] ends rewriting.
+func HttpdProxy(generator string, routes []Route) []byte {
+	buffer := bytes.NewBuffer(nil)
+
+	fmt.Fprintf(buffer, "# Generated by the %s buildpack for %s and %s.\n", generator, ProxyEnv, ConfigFile)
+	for _, module := range []string{"rewrite", "proxy", "proxy_http"} {
+		fmt.Fprintf(buffer, "<IfModule !mod_%s.c>\n  LoadModule %s_module modules/mod_%s.so\n</IfModule>\n", module, module, module)
+	}
+
+	if slices.ContainsFunc(routes, func(route Route) bool { return strings.HasPrefix(route.URL, "https:") }) {
+		buffer.WriteString("<IfModule !mod_ssl.c>\n  LoadModule ssl_module modules/mod_ssl.so\n</IfModule>\n")
+		buffer.WriteString("SSLProxyEngine On\n")
+	}
+
+	buffer.WriteString("RewriteEngine On\n")
+
+	for _, route := range routes {
+		origin, path := route.upstream()
+
+		fmt.Fprintf(buffer, "\nRewriteRule \"^%s(/.*)?$\" \"%s%s$1\" [P,L]\n", regexp.QuoteMeta(route.Path), origin, path)
+		fmt.Fprintf(buffer, "ProxyPassReverse \"%s\" \"%s%s\"\n", route.Path, origin, path)
+	}
+
+	return buffer.Bytes()
+}
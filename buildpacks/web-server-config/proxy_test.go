@@ -0,0 +1,139 @@
+package webserverconfig_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	webserverconfig "github.com/paketo-buildpacks/web-servers/buildpacks/web-server-config"
+	"github.com/paketo-buildpacks/web-servers/internal/serverconf"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testProxy(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("Routes", func() {
+		var workingDir string
+
+		it.Before(func() {
+			workingDir = t.TempDir()
+		})
+
+		it("parses the routes in the environment, longest path first", func() {
+			routes, err := webserverconfig.Routes(workingDir, "/api=http://backend:8080, /api/v2/=https://other-backend/v2")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(routes).To(Equal([]webserverconfig.Route{
+				{Path: "/api/v2", URL: "https://other-backend/v2"},
+				{Path: "/api", URL: "http://backend:8080"},
+			}))
+		})
+
+		context("when the app has a web-servers.toml", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "web-servers.toml"), []byte(`
+[[proxy]]
+path = "/api"
+url = "http://backend:8080"
+
+[[proxy]]
+path = "/auth/"
+url = "http://auth:9000/"
+`), 0600)).To(Succeed())
+			})
+
+			it("merges its routes with those in the environment", func() {
+				routes, err := webserverconfig.Routes(workingDir, "/auth=http://other-auth:9000")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(routes).To(Equal([]webserverconfig.Route{
+					{Path: "/auth", URL: "http://other-auth:9000"},
+					{Path: "/api", URL: "http://backend:8080"},
+				}))
+			})
+		})
+
+		context("when nothing is declared", func() {
+			it("returns no routes", func() {
+				routes, err := webserverconfig.Routes(workingDir, "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(routes).To(BeEmpty())
+			})
+		})
+
+		context("failure cases", func() {
+			it("rejects entries without a URL", func() {
+				_, err := webserverconfig.Routes(workingDir, "/api")
+				Expect(err).To(MatchError(`invalid BP_WEB_SERVER_PROXY route "/api": expected path=url`))
+			})
+
+			it("rejects the root path", func() {
+				_, err := webserverconfig.Routes(workingDir, "/=http://backend")
+				Expect(err).To(MatchError(`invalid proxy path "/": expected an absolute path other than /`))
+			})
+
+			it("rejects URLs with a query", func() {
+				_, err := webserverconfig.Routes(workingDir, "/api=http://backend/?key=value")
+				Expect(err).To(MatchError(`invalid proxy URL "http://backend/?key=value" for /api: cannot contain credentials, a query or a fragment`))
+			})
+
+			it("rejects URLs that cannot be quoted", func() {
+				_, err := webserverconfig.Routes(workingDir, "/api=http://backend/${path}")
+				Expect(err).To(MatchError(`invalid proxy URL "http://backend/${path}" for /api: cannot contain quotes, backslashes, $, ;, braces or spaces`))
+			})
+
+			it("rejects unknown keys in web-servers.toml", func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "web-servers.toml"), []byte("[[proxy]]\nprefix = \"/api\"\n"), 0600)).To(Succeed())
+
+				_, err := webserverconfig.Routes(workingDir, "")
+				Expect(err).To(MatchError("failed to parse web-servers.toml: unknown key proxy.prefix"))
+			})
+		})
+	})
+
+	context("NginxProxy", func() {
+		it("forwards the path and the requests below it", func() {
+			fragments := webserverconfig.NginxProxy("some-buildpack", []webserverconfig.Route{
+				{Path: "/api", URL: "http://backend:8080"},
+				{Path: "/auth", URL: "https://auth/"},
+			})
+			Expect(fragments).To(HaveLen(1))
+
+			fragment := string(fragments[serverconf.NginxServer])
+			Expect(fragment).To(ContainSubstring(`if ($uri ~ "^/api(/|$)") { break; }`))
+			Expect(fragment).To(ContainSubstring(`
+location = /api {
+  proxy_pass http://backend:8080/api;
+  proxy_http_version 1.1;
+  proxy_set_header X-Forwarded-For $proxy_add_x_forwarded_for;
+  proxy_set_header X-Forwarded-Host $host;
+  proxy_set_header X-Forwarded-Proto $scheme;
+}
+`))
+			Expect(fragment).To(ContainSubstring("location ^~ /api/ {\n  proxy_pass http://backend:8080/api/;\n"))
+			Expect(fragment).To(ContainSubstring("location = /auth {\n  proxy_pass https://auth/;\n"))
+			Expect(fragment).To(ContainSubstring("location ^~ /auth/ {\n  proxy_pass https://auth/;\n"))
+			Expect(fragment).To(ContainSubstring("  proxy_ssl_server_name on;\n"))
+		})
+	})
+
+	context("HttpdProxy", func() {
+		it("forwards the routes with proxying rewrite rules", func() {
+			fragment := string(webserverconfig.HttpdProxy("some-buildpack", []webserverconfig.Route{
+				{Path: "/api", URL: "http://backend:8080"},
+				{Path: "/auth", URL: "http://auth/v1/"},
+			}))
+			Expect(fragment).To(ContainSubstring("<IfModule !mod_proxy_http.c>\n  LoadModule proxy_http_module modules/mod_proxy_http.so\n</IfModule>\n"))
+			Expect(fragment).NotTo(ContainSubstring("SSLProxyEngine"))
+			Expect(fragment).To(HaveSuffix(`RewriteEngine On
+
+RewriteRule "^/api(/.*)?$" "http://backend:8080/api$1" [P,L]
+ProxyPassReverse "/api" "http://backend:8080/api"
+
+RewriteRule "^/auth(/.*)?$" "http://auth/v1$1" [P,L]
+ProxyPassReverse "/auth" "http://auth/v1"
+`))
+		})
+	})
+}
@@ -0,0 +1,50 @@
+package webserverconfig
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/paketo-buildpacks/packit/v2/servicebindings"
+	"github.com/paketo-buildpacks/web-servers/internal/serverconf"
+)
+
+// TLS renders the fragments that set the certificate and key in the tls
+// binding. Without a binding it returns no fragments, so the same image can
+// serve plain HTTP. NGINX also needs the ssl parameter on its listen
+// directive, which serverconf.ListenSSL adds, and Caddy serves HTTPS on the
+// site address once its block sets a certificate.
+func TLS(server string, bindings []servicebindings.Binding) (map[serverconf.Context][]byte, error) {
+	name, paths, err := boundEntries(bindings, TLSBindingType, TLSCertificateEntry, TLSKeyEntry)
+	if err != nil || name == "" {
+		return nil, err
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	fmt.Fprintf(buffer, "# Generated at launch from the %s service binding.\n", name)
+
+	switch server {
+	case "nginx":
+		fmt.Fprintf(buffer, "ssl_certificate \"%s\";\n", paths[0])
+		fmt.Fprintf(buffer, "ssl_certificate_key \"%s\";\n", paths[1])
+
+		return map[serverconf.Context][]byte{serverconf.NginxServer: buffer.Bytes()}, nil
+
+	case "httpd":
+		// SSLEngine in the main server context applies to every Listen
+		// directive, so the one for $PORT is left as it is
+		buffer.WriteString("<IfModule !mod_ssl.c>\n  LoadModule ssl_module modules/mod_ssl.so\n</IfModule>\n")
+		buffer.WriteString("SSLEngine on\n")
+		fmt.Fprintf(buffer, "SSLCertificateFile \"%s\"\n", paths[0])
+		fmt.Fprintf(buffer, "SSLCertificateKeyFile \"%s\"\n", paths[1])
+
+		return map[serverconf.Context][]byte{serverconf.Httpd: buffer.Bytes()}, nil
+
+	case "caddy":
+		fmt.Fprintf(buffer, "tls \"%s\" \"%s\"\n", paths[0], paths[1])
+
+		return map[serverconf.Context][]byte{serverconf.Caddy: buffer.Bytes()}, nil
+
+	default:
+		return nil, fmt.Errorf("TLS is not supported for %s", server)
+	}
+}
@@ -0,0 +1,102 @@
+package webserverconfig_test
+
+import (
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/v2/servicebindings"
+	webserverconfig "github.com/paketo-buildpacks/web-servers/buildpacks/web-server-config"
+	"github.com/paketo-buildpacks/web-servers/internal/serverconf"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testTLS(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		bindings []servicebindings.Binding
+	)
+
+	it.Before(func() {
+		bindings = []servicebindings.Binding{
+			{
+				Name: "some-binding",
+				Path: "/bindings/some-binding",
+				Type: "tls",
+				Entries: map[string]*servicebindings.Entry{
+					"tls.crt": servicebindings.NewEntry("/bindings/some-binding/tls.crt"),
+					"tls.key": servicebindings.NewEntry("/bindings/some-binding/tls.key"),
+				},
+			},
+		}
+	})
+
+	it("renders the nginx fragment", func() {
+		fragments, err := webserverconfig.TLS("nginx", bindings)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fragments).To(HaveLen(1))
+		Expect(string(fragments[serverconf.NginxServer])).To(Equal(`# Generated at launch from the some-binding service binding.
+ssl_certificate "/bindings/some-binding/tls.crt";
+ssl_certificate_key "/bindings/some-binding/tls.key";
+`))
+	})
+
+	it("renders the httpd fragment", func() {
+		fragments, err := webserverconfig.TLS("httpd", bindings)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fragments).To(HaveLen(1))
+		Expect(string(fragments[serverconf.Httpd])).To(Equal(`# Generated at launch from the some-binding service binding.
+<IfModule !mod_ssl.c>
+  LoadModule ssl_module modules/mod_ssl.so
+</IfModule>
+SSLEngine on
+SSLCertificateFile "/bindings/some-binding/tls.crt"
+SSLCertificateKeyFile "/bindings/some-binding/tls.key"
+`))
+	})
+
+	it("renders the caddy fragment", func() {
+		fragments, err := webserverconfig.TLS("caddy", bindings)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fragments).To(HaveLen(1))
+		Expect(string(fragments[serverconf.Caddy])).To(Equal(`# Generated at launch from the some-binding service binding.
+tls "/bindings/some-binding/tls.crt" "/bindings/some-binding/tls.key"
+`))
+	})
+
+	context("when there is no binding", func() {
+		it("renders nothing", func() {
+			fragments, err := webserverconfig.TLS("nginx", nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fragments).To(BeEmpty())
+		})
+	})
+
+	context("failure cases", func() {
+		context("when there is more than one binding", func() {
+			it("returns an error", func() {
+				_, err := webserverconfig.TLS("nginx", append(bindings, bindings[0]))
+				Expect(err).To(MatchError("found 2 service bindings of type tls, expected at most one"))
+			})
+		})
+
+		context("when the binding has no key", func() {
+			it.Before(func() {
+				delete(bindings[0].Entries, "tls.key")
+			})
+
+			it("returns an error", func() {
+				_, err := webserverconfig.TLS("nginx", bindings)
+				Expect(err).To(MatchError("service binding some-binding has no tls.key entry"))
+			})
+		})
+
+		context("when the server is not supported", func() {
+			it("returns an error", func() {
+				_, err := webserverconfig.TLS("some-server", bindings)
+				Expect(err).To(MatchError("TLS is not supported for some-server"))
+			})
+		})
+	})
+}
@@ -0,0 +1,42 @@
+package webserverselector
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/fs"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+// Build reports the web server chosen during detection. It contributes no
+// layers; the chosen server's own buildpack installs and configures it.
+func Build(logger scribe.Emitter) packit.BuildFunc {
+	return func(context packit.BuildContext) (packit.BuildResult, error) {
+		logger.Title("%s %s", context.BuildpackInfo.Name, context.BuildpackInfo.Version)
+
+		server, reason, err := Selected(context.Plan)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		logger.Process("Chose %s because %s", server.Name, reason)
+
+		// The server's buildpack reads BP_WEB_SERVER too, and generates its
+		// configuration over the app's
+		if name := os.Getenv("BP_WEB_SERVER"); name == server.Name && server.ZeroConfig {
+			exists, err := fs.Exists(filepath.Join(context.WorkingDir, server.ConfigFile))
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			if exists {
+				logger.Subprocess("Warning: BP_WEB_SERVER makes the %s buildpack generate %s in place of the one in the app; to keep it, unset BP_WEB_SERVER and remove the configuration of the other servers", server.Name, server.ConfigFile)
+			}
+		}
+
+		logger.Break()
+
+		return packit.BuildResult{}, nil
+	}
+}
@@ -0,0 +1,76 @@
+package webserverselector_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	webserverselector "github.com/paketo-buildpacks/web-servers/buildpacks/web-server-selector"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testBuild(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+		buffer     *bytes.Buffer
+		build      packit.BuildFunc
+		buildCtx   packit.BuildContext
+	)
+
+	it.Before(func() {
+		workingDir = t.TempDir()
+		buffer = bytes.NewBuffer(nil)
+
+		build = webserverselector.Build(scribe.NewEmitter(buffer))
+		buildCtx = packit.BuildContext{
+			BuildpackInfo: packit.Info{
+				Name:    "Some Buildpack",
+				Version: "some-version",
+			},
+			WorkingDir: workingDir,
+			Plan: packit.BuildpackPlan{
+				Entries: []packit.BuildpackPlanEntry{
+					{Name: webserverselector.Selection},
+					{
+						Name: webserverselector.Selection,
+						Metadata: map[string]interface{}{
+							"server": "httpd",
+							"reason": `BP_WEB_SERVER is set to "httpd"`,
+						},
+					},
+				},
+			},
+		}
+	})
+
+	it("logs the selected server and the reason it was chosen", func() {
+		result, err := build(buildCtx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(packit.BuildResult{}))
+
+		Expect(buffer.String()).To(ContainSubstring("Some Buildpack some-version"))
+		Expect(buffer.String()).To(ContainSubstring(`Chose httpd because BP_WEB_SERVER is set to "httpd"`))
+		Expect(buffer.String()).NotTo(ContainSubstring("Warning"))
+	})
+
+	context("when BP_WEB_SERVER names a server whose configuration the app provides", func() {
+		it.Before(func() {
+			t.Setenv("BP_WEB_SERVER", "httpd")
+			Expect(os.WriteFile(filepath.Join(workingDir, "httpd.conf"), nil, 0600)).To(Succeed())
+		})
+
+		it("warns that the server's buildpack replaces it", func() {
+			_, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(buffer.String()).To(ContainSubstring("Warning: BP_WEB_SERVER makes the httpd buildpack generate httpd.conf in place of the one in the app; to keep it, unset BP_WEB_SERVER and remove the configuration of the other servers"))
+		})
+	})
+}
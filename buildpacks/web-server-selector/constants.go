@@ -0,0 +1,29 @@
+package webserverselector
+
+// Selection is the name of the build plan entry that carries the chosen web
+// server and the reason it was chosen from detection through to build.
+const Selection = "web-server-selection"
+
+// Server describes a web server that can be selected with BP_WEB_SERVER.
+type Server struct {
+	// Name is both the accepted BP_WEB_SERVER value and the build plan entry
+	// provided by the buildpack that installs the server.
+	Name string
+
+	// ConfigFile is the file in the application source that signals the
+	// server is wanted.
+	ConfigFile string
+
+	// ZeroConfig is set when the server's buildpack generates ConfigFile
+	// whenever BP_WEB_SERVER names it, replacing the one in the application
+	// source.
+	ZeroConfig bool
+}
+
+// Servers lists the supported web servers in the order they appear within
+// each set of order groups in the composite buildpack.toml.
+var Servers = []Server{
+	{Name: "nginx", ConfigFile: "nginx.conf", ZeroConfig: true},
+	{Name: "httpd", ConfigFile: "httpd.conf", ZeroConfig: true},
+	{Name: "caddy", ConfigFile: "Caddyfile"},
+}
@@ -0,0 +1,101 @@
+package webserverselector
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/fs"
+)
+
+// Detect requires the web server named by BP_WEB_SERVER so that only order
+// groups containing that server can pass. When BP_WEB_SERVER is unset, any of
+// the supported servers satisfies the requirement and order precedence
+// decides, exactly as it would without this buildpack.
+func Detect() packit.DetectFunc {
+	return func(context packit.DetectContext) (packit.DetectResult, error) {
+		var found []string
+		for _, server := range Servers {
+			exists, err := fs.Exists(filepath.Join(context.WorkingDir, server.ConfigFile))
+			if err != nil {
+				return packit.DetectResult{}, err
+			}
+
+			if exists {
+				found = append(found, server.ConfigFile)
+			}
+		}
+
+		if name, ok := os.LookupEnv("BP_WEB_SERVER"); ok && name != "" {
+			for _, server := range Servers {
+				if server.Name == name {
+					return packit.DetectResult{
+						Plan: plan(server, fmt.Sprintf("BP_WEB_SERVER is set to %q", name)),
+					}, nil
+				}
+			}
+
+			var names []string
+			for _, server := range Servers {
+				names = append(names, server.Name)
+			}
+
+			return packit.DetectResult{}, fmt.Errorf("unsupported BP_WEB_SERVER value %q: must be one of %s", name, strings.Join(names, ", "))
+		}
+
+		var plans []packit.BuildPlan
+		for _, server := range Servers {
+			plans = append(plans, plan(server, reason(server, found)))
+		}
+
+		return packit.DetectResult{
+			Plan: packit.BuildPlan{
+				Provides: plans[0].Provides,
+				Requires: plans[0].Requires,
+				Or:       plans[1:],
+			},
+		}, nil
+	}
+}
+
+func plan(server Server, reason string) packit.BuildPlan {
+	return packit.BuildPlan{
+		Provides: []packit.BuildPlanProvision{
+			{Name: Selection},
+		},
+		Requires: []packit.BuildPlanRequirement{
+			{
+				Name: Selection,
+				Metadata: map[string]interface{}{
+					"server": server.Name,
+					"reason": reason,
+				},
+			},
+			{Name: server.Name},
+		},
+	}
+}
+
+func reason(server Server, found []string) string {
+	var others []string
+	selected := false
+	for _, file := range found {
+		if file == server.ConfigFile {
+			selected = true
+			continue
+		}
+		others = append(others, file)
+	}
+
+	if !selected {
+		return "it is the first web server in the buildpack order to pass detection"
+	}
+
+	if len(others) == 0 {
+		return fmt.Sprintf("%s was found", server.ConfigFile)
+	}
+
+	return fmt.Sprintf("%s was found alongside %s; set BP_WEB_SERVER to choose a different server", server.ConfigFile, strings.Join(others, ", "))
+}
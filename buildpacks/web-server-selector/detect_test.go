@@ -0,0 +1,139 @@
+package webserverselector_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	webserverselector "github.com/paketo-buildpacks/web-servers/buildpacks/web-server-selector"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDetect(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+		detect     packit.DetectFunc
+	)
+
+	it.Before(func() {
+		workingDir = t.TempDir()
+
+		detect = webserverselector.Detect()
+	})
+
+	context("when BP_WEB_SERVER is set", func() {
+		it.Before(func() {
+			t.Setenv("BP_WEB_SERVER", "httpd")
+
+			Expect(os.WriteFile(filepath.Join(workingDir, "nginx.conf"), nil, 0600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(workingDir, "httpd.conf"), nil, 0600)).To(Succeed())
+		})
+
+		it("requires only the selected server", func() {
+			result, err := detect(packit.DetectContext{
+				WorkingDir: workingDir,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Plan).To(Equal(packit.BuildPlan{
+				Provides: []packit.BuildPlanProvision{
+					{Name: webserverselector.Selection},
+				},
+				Requires: []packit.BuildPlanRequirement{
+					{
+						Name: webserverselector.Selection,
+						Metadata: map[string]interface{}{
+							"server": "httpd",
+							"reason": `BP_WEB_SERVER is set to "httpd"`,
+						},
+					},
+					{Name: "httpd"},
+				},
+			}))
+		})
+	})
+
+	context("when BP_WEB_SERVER is not set", func() {
+		context("and the app contains both nginx.conf and httpd.conf", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "nginx.conf"), nil, 0600)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "httpd.conf"), nil, 0600)).To(Succeed())
+			})
+
+			it("accepts any server, explaining each choice", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Plan.Requires).To(Equal([]packit.BuildPlanRequirement{
+					{
+						Name: webserverselector.Selection,
+						Metadata: map[string]interface{}{
+							"server": "nginx",
+							"reason": "nginx.conf was found alongside httpd.conf; set BP_WEB_SERVER to choose a different server",
+						},
+					},
+					{Name: "nginx"},
+				}))
+
+				Expect(result.Plan.Or).To(HaveLen(2))
+				Expect(result.Plan.Or[0].Requires).To(Equal([]packit.BuildPlanRequirement{
+					{
+						Name: webserverselector.Selection,
+						Metadata: map[string]interface{}{
+							"server": "httpd",
+							"reason": "httpd.conf was found alongside nginx.conf; set BP_WEB_SERVER to choose a different server",
+						},
+					},
+					{Name: "httpd"},
+				}))
+				Expect(result.Plan.Or[1].Requires).To(Equal([]packit.BuildPlanRequirement{
+					{
+						Name: webserverselector.Selection,
+						Metadata: map[string]interface{}{
+							"server": "caddy",
+							"reason": "it is the first web server in the buildpack order to pass detection",
+						},
+					},
+					{Name: "caddy"},
+				}))
+			})
+		})
+
+		context("and the app contains a single server configuration", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "Caddyfile"), nil, 0600)).To(Succeed())
+			})
+
+			it("names the configuration file as the reason", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Plan.Or[1].Requires[0].Metadata).To(Equal(map[string]interface{}{
+					"server": "caddy",
+					"reason": "Caddyfile was found",
+				}))
+			})
+		})
+	})
+
+	context("failure cases", func() {
+		context("when BP_WEB_SERVER names an unsupported server", func() {
+			it.Before(func() {
+				t.Setenv("BP_WEB_SERVER", "lighttpd")
+			})
+
+			it("returns an error", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).To(MatchError(`unsupported BP_WEB_SERVER value "lighttpd": must be one of nginx, httpd, caddy`))
+			})
+		})
+	})
+}
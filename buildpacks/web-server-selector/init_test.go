@@ -0,0 +1,16 @@
+package webserverselector_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitWebServerSelector(t *testing.T) {
+	suite := spec.New("web-server-selector", spec.Report(report.Terminal{}), spec.Sequential())
+	suite("Detect", testDetect)
+	suite("Build", testBuild)
+	suite("Selected", testSelected)
+	suite.Run(t)
+}
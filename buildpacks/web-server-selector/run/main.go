@@ -0,0 +1,18 @@
+package main
+
+import (
+	"os"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	webserverselector "github.com/paketo-buildpacks/web-servers/buildpacks/web-server-selector"
+)
+
+func main() {
+	logger := scribe.NewEmitter(os.Stdout).WithLevel(os.Getenv("BP_LOG_LEVEL"))
+
+	packit.Run(
+		webserverselector.Detect(),
+		webserverselector.Build(logger),
+	)
+}
@@ -0,0 +1,51 @@
+package webserverselector
+
+import (
+	"fmt"
+
+	"github.com/paketo-buildpacks/packit/v2"
+)
+
+// SelectionPlan is the build plan of a buildpack that reads the selected
+// server with Selected. The lifecycle only passes a requirement to the
+// buildpacks that provide it, so these buildpacks provide Selection as well as
+// requiring it, after the web server selector in the group.
+func SelectionPlan() packit.BuildPlan {
+	return packit.BuildPlan{
+		Provides: []packit.BuildPlanProvision{
+			{Name: Selection},
+		},
+		Requires: []packit.BuildPlanRequirement{
+			{Name: Selection},
+		},
+	}
+}
+
+// Selected returns the server chosen during detection, from the metadata of
+// the Selection requirement of the web server selector, along with the reason
+// it was chosen. That is the server of the order group that passed, whatever
+// files are in the application source at build time.
+func Selected(plan packit.BuildpackPlan) (Server, string, error) {
+	for _, entry := range plan.Entries {
+		if entry.Name != Selection {
+			continue
+		}
+
+		name, ok := entry.Metadata["server"].(string)
+		if !ok {
+			continue
+		}
+
+		reason, _ := entry.Metadata["reason"].(string)
+
+		for _, server := range Servers {
+			if server.Name == name {
+				return server, reason, nil
+			}
+		}
+
+		return Server{}, "", fmt.Errorf("unsupported web server %q in the %s build plan entry", name, Selection)
+	}
+
+	return Server{}, "", fmt.Errorf("no web server was selected: the buildpack plan has no %s entry with a server", Selection)
+}
@@ -0,0 +1,73 @@
+package webserverselector_test
+
+import (
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	webserverselector "github.com/paketo-buildpacks/web-servers/buildpacks/web-server-selector"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testSelected(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		plan packit.BuildpackPlan
+	)
+
+	it.Before(func() {
+		plan = packit.BuildpackPlan{
+			Entries: []packit.BuildpackPlanEntry{
+				{Name: "node"},
+				{Name: webserverselector.Selection},
+				{
+					Name: webserverselector.Selection,
+					Metadata: map[string]interface{}{
+						"server": "httpd",
+						"reason": "httpd.conf was found",
+					},
+				},
+			},
+		}
+	})
+
+	it("returns the server in the metadata of the selection entry", func() {
+		server, reason, err := webserverselector.Selected(plan)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(server).To(Equal(webserverselector.Server{Name: "httpd", ConfigFile: "httpd.conf", ZeroConfig: true}))
+		Expect(reason).To(Equal("httpd.conf was found"))
+	})
+
+	it("is required and provided by the selection plan", func() {
+		Expect(webserverselector.SelectionPlan()).To(Equal(packit.BuildPlan{
+			Provides: []packit.BuildPlanProvision{{Name: "web-server-selection"}},
+			Requires: []packit.BuildPlanRequirement{{Name: "web-server-selection"}},
+		}))
+	})
+
+	context("failure cases", func() {
+		context("when no entry names a server", func() {
+			it.Before(func() {
+				plan.Entries = plan.Entries[:2]
+			})
+
+			it("returns an error", func() {
+				_, _, err := webserverselector.Selected(plan)
+				Expect(err).To(MatchError("no web server was selected: the buildpack plan has no web-server-selection entry with a server"))
+			})
+		})
+
+		context("when the entry names an unsupported server", func() {
+			it.Before(func() {
+				plan.Entries[2].Metadata["server"] = "lighttpd"
+			})
+
+			it("returns an error", func() {
+				_, _, err := webserverselector.Selected(plan)
+				Expect(err).To(MatchError(`unsupported web server "lighttpd" in the web-server-selection build plan entry`))
+			})
+		})
+	})
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/paketo-buildpacks/web-servers/internal/ocilayout"
+)
+
+func main() {
+	output := flag.String("output", "", "directory to write the OCI image layout to (required)")
+	ref := flag.String("ref", "", "image reference to record as the ref name of the index (optional)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s -output <dir> [-ref <ref>] <archive>...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *output == "" || flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	err := ocilayout.Assemble(*output, *ref, flag.Args()...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("wrote %d image(s) to %s\n", flag.NArg(), *output)
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/paketo-buildpacks/web-servers/internal/manifest"
+)
+
+func main() {
+	root := flag.String("root", ".", "path to the root of the web-servers repository")
+	flag.Parse()
+
+	m, err := manifest.Load(*root)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	problems := m.Check()
+	for _, problem := range problems {
+		fmt.Println(problem)
+	}
+
+	if len(problems) > 0 {
+		fmt.Printf("\nfound %d problem(s) in buildpack.toml and package.toml\n", len(problems))
+		os.Exit(1)
+	}
+
+	fmt.Println("buildpack.toml and package.toml are consistent")
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/paketo-buildpacks/web-servers/internal/matrix"
+)
+
+func main() {
+	root := flag.String("root", ".", "path to the root of the web-servers repository")
+	check := flag.Bool("check", false, "fail if buildpack.toml or package.toml differ from the generated output instead of writing them")
+	flag.Parse()
+
+	definition, err := matrix.Load(filepath.Join(*root, "matrix.toml"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	files, err := matrix.Generate(*root, definition)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	var drifted bool
+	for _, file := range []struct {
+		name    string
+		content []byte
+	}{
+		{"buildpack.toml", files.Buildpack},
+		{"package.toml", files.Package},
+	} {
+		path := filepath.Join(*root, file.name)
+
+		if *check {
+			current, err := os.ReadFile(path)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+
+			if !bytes.Equal(current, file.content) {
+				fmt.Printf("%s is out of date with matrix.toml\n", file.name)
+				drifted = true
+			}
+
+			continue
+		}
+
+		err = os.WriteFile(path, file.content, 0644)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	if drifted {
+		fmt.Println("\nrun 'go run ./cmd/generate-orders' and commit the result")
+		os.Exit(1)
+	}
+
+	if *check {
+		fmt.Println("buildpack.toml and package.toml match matrix.toml")
+	}
+}
@@ -0,0 +1,14 @@
+package main_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitSimulateDetect(t *testing.T) {
+	suite := spec.New("simulate-detect", spec.Report(report.Terminal{}), spec.Sequential())
+	suite("SimulateDetect", testSimulateDetect)
+	suite.Run(t)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/paketo-buildpacks/web-servers/internal/manifest"
+	"github.com/paketo-buildpacks/web-servers/internal/simulator"
+)
+
+type env map[string]string
+
+func (e env) String() string {
+	return fmt.Sprint(map[string]string(e))
+}
+
+func (e env) Set(value string) error {
+	name, val, ok := strings.Cut(value, "=")
+	if !ok {
+		return fmt.Errorf("expected NAME=VALUE, got %q", value)
+	}
+
+	e[name] = val
+	return nil
+}
+
+func main() {
+	vars := env{}
+	root := flag.String("root", ".", "path to the root of the web-servers repository")
+	flag.Var(vars, "env", "build-time environment variable as NAME=VALUE (may be repeated)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <app-dir>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	m, err := manifest.Load(*root)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	results, err := simulator.Simulate(m.Buildpack.Order, simulator.App{Dir: flag.Arg(0), Env: vars})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if len(results) == 0 {
+		fmt.Fprintln(os.Stderr, "no orders in buildpack.toml")
+		os.Exit(1)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, result := range results {
+		if !result.Pass {
+			fmt.Fprintf(w, "Group %d fails:\n", result.Index)
+			for _, entry := range result.Failures() {
+				fmt.Fprintf(w, "  %s\t%s\n", entry.ID, entry.Reason)
+			}
+			continue
+		}
+
+		fmt.Fprintf(w, "\nGroup %d passes:\n", result.Index)
+		for _, entry := range result.Entries {
+			status := "pass"
+			if !entry.Pass {
+				status = "skip"
+			}
+
+			id := fmt.Sprintf("%s@%s", entry.ID, entry.Version)
+			if entry.Optional {
+				id += " (optional)"
+			}
+
+			fmt.Fprintf(w, "  %s\t%s\t%s\n", status, id, entry.Reason)
+		}
+	}
+	w.Flush()
+
+	if last := results[len(results)-1]; !last.Pass {
+		fmt.Println("\nno group passes detection")
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,58 @@
+package main_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega/gexec"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testSimulateDetect(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		command string
+		root    string
+	)
+
+	it.Before(func() {
+		var err error
+		command, err = gexec.Build("github.com/paketo-buildpacks/web-servers/cmd/simulate-detect")
+		Expect(err).NotTo(HaveOccurred())
+
+		root = t.TempDir()
+		Expect(os.WriteFile(filepath.Join(root, "package.toml"), nil, 0600)).To(Succeed())
+	})
+
+	it.After(func() {
+		gexec.CleanupBuildArtifacts()
+	})
+
+	context("failure cases", func() {
+		context("when buildpack.toml has no orders", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(root, "buildpack.toml"), []byte(`api = "0.7"
+
+[buildpack]
+  id = "some-org/some-buildpack"
+  version = "some-version"
+`), 0600)).To(Succeed())
+			})
+
+			it("reports it and exits with an error", func() {
+				session, err := gexec.Start(exec.Command(command, "-root", root, t.TempDir()), nil, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Eventually(session).Should(gexec.Exit(1))
+
+				Expect(string(session.Err.Contents())).To(Equal("no orders in buildpack.toml\n"))
+				Expect(session.Out.Contents()).To(BeEmpty())
+			})
+		})
+	})
+}
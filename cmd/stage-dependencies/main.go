@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/paketo-buildpacks/web-servers/internal/airgap"
+	"github.com/paketo-buildpacks/web-servers/internal/manifest"
+)
+
+func main() {
+	root := flag.String("root", ".", "directory containing the buildpack.toml and package.toml to stage dependencies for")
+	source := flag.String("source", "", "directory of OCI image layouts and .cnb files to resolve dependencies from (required)")
+	platform := flag.String("target", "linux/"+runtime.GOARCH, "os/arch of the buildpackages to stage")
+	flag.Parse()
+
+	if *source == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	goos, arch, ok := strings.Cut(*platform, "/")
+	if !ok {
+		fmt.Fprintf(os.Stderr, "invalid target %q: expected os/arch\n", *platform)
+		os.Exit(2)
+	}
+	target := manifest.Target{OS: goos, Arch: arch}
+
+	m, err := manifest.Load(*root)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	artifacts, err := airgap.Scan(*source, target)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	pkg, err := airgap.Stage(m, artifacts, *root, target)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	err = os.WriteFile(filepath.Join(*root, "package.toml"), pkg.Encode(), 0644)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	var staged int
+	for _, dependency := range pkg.Dependencies {
+		if strings.HasPrefix(dependency.URI, airgap.StagingDir+"/") {
+			staged++
+		}
+	}
+
+	fmt.Printf("staged %d buildpackage(s) for %s into %s\n", staged, target, filepath.Join(*root, airgap.StagingDir))
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/paketo-buildpacks/web-servers/internal/manifest"
+	"github.com/paketo-buildpacks/web-servers/internal/ocilayout"
+)
+
+func main() {
+	root := flag.String("root", ".", "directory containing the buildpack.toml and package.toml the layout was packaged from")
+	layout := flag.String("layout", "", "path to the OCI image layout directory to verify (required)")
+	flag.Parse()
+
+	if *layout == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	m, err := manifest.Load(*root)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	err = ocilayout.Verify(*layout, m)
+	if err != nil {
+		fmt.Println(err)
+		fmt.Printf("\n%s does not match buildpack.toml and package.toml\n", *layout)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%s contains every buildpack for %d target(s)\n", *layout, len(m.Package.Targets))
+}
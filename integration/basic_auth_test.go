@@ -0,0 +1,129 @@
+package integration_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testBasicAuth(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+	)
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	for _, server := range []struct {
+		name    string
+		fixture string
+	}{
+		{name: "NGINX", fixture: "nginx"},
+		{name: "HTTPD", fixture: "httpd"},
+	} {
+		server := server
+
+		context("when serving an app with basic authentication with "+server.name, func() {
+			var (
+				image      occam.Image
+				containers []occam.Container
+
+				name   string
+				source string
+			)
+
+			it.Before(func() {
+				var err error
+				name, err = occam.RandomName()
+				Expect(err).NotTo(HaveOccurred())
+
+				source, err = occam.Source(filepath.Join("testdata", server.fixture))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			it.After(func() {
+				for _, container := range containers {
+					Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+				}
+				Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+				Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+				Expect(os.RemoveAll(source)).To(Succeed())
+			})
+
+			it("requires the users of the bound htpasswd file", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = pack.WithNoColor().Build.
+					WithBuildpacks(webServersBuildpack).
+					WithPullPolicy("never").
+					WithEnv(map[string]string{"BP_WEB_SERVER_BASIC_AUTH": "true"}).
+					Execute(name, source)
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Web Server Configuration")))
+				Expect(logs).To(ContainLines(ContainSubstring("Enabling basic authentication at launch")))
+
+				binding, err := filepath.Abs(filepath.Join("testdata", "htpasswd_binding"))
+				Expect(err).NotTo(HaveOccurred())
+
+				container, err := docker.Container.Run.
+					WithEnv(map[string]string{
+						"PORT":                 "8080",
+						"SERVICE_BINDING_ROOT": "/bindings",
+					}).
+					WithPublish("8080").
+					WithVolumes(fmt.Sprintf("%s:/bindings/htpasswd", binding)).
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+				containers = append(containers, container)
+
+				Eventually(container).Should(BeAvailable())
+
+				request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://localhost:%s/index.html", container.HostPort("8080")), nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				response, err := http.DefaultClient.Do(request)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response.Body.Close()).To(Succeed())
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+				Expect(response.Header.Get("WWW-Authenticate")).To(ContainSubstring(`Basic realm="Restricted"`))
+
+				request.SetBasicAuth("some-user", "some-wrong-password")
+				response, err = http.DefaultClient.Do(request)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response.Body.Close()).To(Succeed())
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+
+				request.SetBasicAuth("some-user", "some-password")
+				response, err = http.DefaultClient.Do(request)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response.Body.Close()).To(Succeed())
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+				// Without the binding, the same image serves without authentication
+				container, err = docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+				containers = append(containers, container)
+
+				Eventually(container).Should(Serve(ContainSubstring("<body>Hello World!</body>")).OnPort(8080).WithEndpoint("/index.html"))
+			})
+		})
+	}
+}
@@ -29,10 +29,21 @@ func TestIntegration(t *testing.T) {
 	format.MaxLength = 0
 
 	suite := spec.New("Integration", spec.Parallel(), spec.Report(report.Terminal{}))
-	suite("HTTPD", testHttpd)
-	suite("NGINX", testNginx)
-	suite("NPM Frontend", testNPMFrontend)
-	suite("Yarn Frontend", testYarnFrontend)
+	suite("Basic Auth", testBasicAuth)
+	suite("Health Check", testHealthCheck)
+	suite("Metrics", testMetrics)
+	suite("NGINX Zero Config", testNginxZeroConfig)
+	suite("Order Groups", testOrderGroups)
+	suite("Precompress", testPrecompress)
+	suite("Proxy", testProxy)
+	suite("Rebuild", testRebuild)
+	suite("Redirects", testRedirects)
+	suite("Reproducible Builds", testReproducibleBuilds)
+	suite("Runtime Environment", testRuntimeEnv)
+	suite("SBOM", testSBOM)
+	suite("Security Headers", testSecurityHeaders)
+	suite("TLS", testTLS)
 	suite("Source Removal", testSourceRemoval)
+	suite("Web Server Selection", testWebServerSelection)
 	suite.Run(t)
 }
@@ -0,0 +1,569 @@
+package integration_test
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+// orderGroup describes the app that covers one order group of the buildpack
+// and what building and serving it shows. Adding a group to orderGroups runs
+// the basic, utility buildpack and CA certificate scenarios for it.
+type orderGroup struct {
+	// name describes the app in the spec output.
+	name string
+
+	// fixture is the app below testdata. When caddy is set, its nginx.conf is
+	// replaced with caddyfile, to cover the Caddy groups of build steps that
+	// have no Caddy fixture of their own.
+	fixture string
+	caddy   bool
+
+	// server selects the start command of the Procfile that the utility
+	// buildpack scenario adds, from startCommands.
+	server string
+
+	// env is the build environment that the app needs.
+	env map[string]string
+
+	// buildpacks are the "Buildpack for" lines that the build prints, and
+	// forbidden the ones that it must not print.
+	buildpacks []string
+	forbidden  []string
+
+	// environmentVariables is the position of the Environment Variables
+	// buildpack in the image metadata when the utility buildpacks run.
+	environmentVariables int
+
+	// body is expected in the response for endpoint.
+	endpoint string
+	body     string
+
+	// launchSBOM are the packages that the SBOM of the image lists, and
+	// buildSBOM those that the SBOM of the build lists, in both CycloneDX
+	// and SPDX.
+	launchSBOM []string
+	buildSBOM  []string
+
+	// caCertificates is set when testdata/ca_cert_apps holds a copy of the
+	// fixture that serves HTTPS with a client certificate.
+	caCertificates bool
+}
+
+// caddyfile replaces the nginx.conf of a frontend fixture for the Caddy
+// variants of orderGroups.
+const caddyfile = `{
+	admin off
+	auto_https off
+}
+
+:{$PORT} {
+	root * build
+	file_server
+}
+`
+
+// startCommands are the Procfile start commands for each server.
+var startCommands = map[string]string{
+	"nginx": "nginx -p $PWD -c nginx.conf -g 'pid /tmp/server.pid;'",
+	"httpd": "httpd -f /workspace/httpd.conf -k start -DFOREGROUND",
+	"caddy": "caddy run --config Caddyfile --adapter caddyfile",
+}
+
+var (
+	nodeRunScripts = map[string]string{"BP_NODE_RUN_SCRIPTS": "build"}
+	bunRunScripts  = map[string]string{"BP_BUN_RUN_SCRIPTS": "build"}
+)
+
+// orderGroups holds one app per order group of matrix.toml.
+var orderGroups = []orderGroup{
+	{
+		name:                 "a Yarn frontend app using NGINX",
+		fixture:              "yarn-nginx-javascript-frontend",
+		server:               "nginx",
+		env:                  nodeRunScripts,
+		buildpacks:           []string{"Node Engine", "Yarn", "Yarn Install", "Node Run Script", "Nginx Server"},
+		forbidden:            []string{"Apache HTTP Server", "Caddy"},
+		environmentVariables: 9,
+		endpoint:             "/index.html",
+		body:                 "<title>React App</title>",
+		launchSBOM:           []string{"Nginx Server"},
+		buildSBOM:            []string{"Node Engine", "react"},
+		caCertificates:       true,
+	},
+	{
+		name:                 "a Yarn frontend app using HTTPD",
+		fixture:              "yarn-httpd-javascript-frontend",
+		server:               "httpd",
+		env:                  nodeRunScripts,
+		buildpacks:           []string{"Node Engine", "Yarn", "Yarn Install", "Node Run Script", "Apache HTTP Server"},
+		forbidden:            []string{"Nginx Server", "Caddy"},
+		environmentVariables: 9,
+		endpoint:             "/index.html",
+		body:                 "<title>React App</title>",
+		launchSBOM:           []string{"Apache HTTP Server"},
+		buildSBOM:            []string{"Node Engine", "react"},
+		caCertificates:       true,
+	},
+	{
+		name:                 "a Yarn frontend app using Caddy",
+		fixture:              "yarn-nginx-javascript-frontend",
+		caddy:                true,
+		server:               "caddy",
+		env:                  nodeRunScripts,
+		buildpacks:           []string{"Node Engine", "Yarn", "Yarn Install", "Node Run Script", "Caddy"},
+		forbidden:            []string{"Nginx Server", "Apache HTTP Server"},
+		environmentVariables: 9,
+		endpoint:             "/index.html",
+		body:                 "<title>React App</title>",
+		buildSBOM:            []string{"Node Engine", "react"},
+	},
+	{
+		name:                 "a PNPM frontend app using NGINX",
+		fixture:              "pnpm-nginx-javascript-frontend",
+		server:               "nginx",
+		env:                  nodeRunScripts,
+		buildpacks:           []string{"Node Engine", "PNPM", "PNPM Install", "Node Run Script", "Nginx Server"},
+		forbidden:            []string{"Apache HTTP Server", "Caddy"},
+		environmentVariables: 9,
+		endpoint:             "/index.html",
+		body:                 "<title>PNPM App</title>",
+		launchSBOM:           []string{"Nginx Server"},
+		buildSBOM:            []string{"Node Engine"},
+		caCertificates:       true,
+	},
+	{
+		name:                 "a PNPM frontend app using HTTPD",
+		fixture:              "pnpm-httpd-javascript-frontend",
+		server:               "httpd",
+		env:                  nodeRunScripts,
+		buildpacks:           []string{"Node Engine", "PNPM", "PNPM Install", "Node Run Script", "Apache HTTP Server"},
+		forbidden:            []string{"Nginx Server", "Caddy"},
+		environmentVariables: 9,
+		endpoint:             "/index.html",
+		body:                 "<title>PNPM App</title>",
+		launchSBOM:           []string{"Apache HTTP Server"},
+		buildSBOM:            []string{"Node Engine"},
+		caCertificates:       true,
+	},
+	{
+		name:                 "a PNPM frontend app using Caddy",
+		fixture:              "pnpm-nginx-javascript-frontend",
+		caddy:                true,
+		server:               "caddy",
+		env:                  nodeRunScripts,
+		buildpacks:           []string{"Node Engine", "PNPM", "PNPM Install", "Node Run Script", "Caddy"},
+		forbidden:            []string{"Nginx Server", "Apache HTTP Server"},
+		environmentVariables: 9,
+		endpoint:             "/index.html",
+		body:                 "<title>PNPM App</title>",
+		buildSBOM:            []string{"Node Engine"},
+	},
+	{
+		name:                 "a Bun frontend app using NGINX",
+		fixture:              "bun-nginx-javascript-frontend",
+		server:               "nginx",
+		env:                  bunRunScripts,
+		buildpacks:           []string{"Bun", "Bun Install", "Bun Run Script", "Nginx Server"},
+		forbidden:            []string{"Node Engine", "Apache HTTP Server", "Caddy"},
+		environmentVariables: 8,
+		endpoint:             "/index.html",
+		body:                 "<title>Bun App</title>",
+		launchSBOM:           []string{"Nginx Server"},
+		caCertificates:       true,
+	},
+	{
+		name:                 "a Bun frontend app using HTTPD",
+		fixture:              "bun-httpd-javascript-frontend",
+		server:               "httpd",
+		env:                  bunRunScripts,
+		buildpacks:           []string{"Bun", "Bun Install", "Bun Run Script", "Apache HTTP Server"},
+		forbidden:            []string{"Node Engine", "Nginx Server", "Caddy"},
+		environmentVariables: 8,
+		endpoint:             "/index.html",
+		body:                 "<title>Bun App</title>",
+		launchSBOM:           []string{"Apache HTTP Server"},
+		caCertificates:       true,
+	},
+	{
+		name:                 "a Bun frontend app using Caddy",
+		fixture:              "bun-nginx-javascript-frontend",
+		caddy:                true,
+		server:               "caddy",
+		env:                  bunRunScripts,
+		buildpacks:           []string{"Bun", "Bun Install", "Bun Run Script", "Caddy"},
+		forbidden:            []string{"Node Engine", "Nginx Server", "Apache HTTP Server"},
+		environmentVariables: 8,
+		endpoint:             "/index.html",
+		body:                 "<title>Bun App</title>",
+	},
+	{
+		name:                 "a NPM frontend app using NGINX",
+		fixture:              "npm-nginx-javascript-frontend",
+		server:               "nginx",
+		env:                  nodeRunScripts,
+		buildpacks:           []string{"Node Engine", "NPM Install", "Node Run Script", "Nginx Server"},
+		forbidden:            []string{"Apache HTTP Server", "Caddy"},
+		environmentVariables: 8,
+		endpoint:             "/index.html",
+		body:                 "<title>React App</title>",
+		launchSBOM:           []string{"Nginx Server"},
+		buildSBOM:            []string{"Node Engine", "react"},
+		caCertificates:       true,
+	},
+	{
+		name:                 "a NPM frontend app without web server configuration",
+		fixture:              "npm-zero-config-javascript-frontend",
+		server:               "nginx",
+		env:                  nodeRunScripts,
+		buildpacks:           []string{"Node Engine", "NPM Install", "Node Run Script", "Nginx Zero Config", "Nginx Server"},
+		forbidden:            []string{"Apache HTTP Server", "Caddy"},
+		environmentVariables: 9,
+		endpoint:             "/index.html",
+		body:                 "<title>Frontend App</title>",
+		launchSBOM:           []string{"Nginx Server"},
+		buildSBOM:            []string{"Node Engine"},
+	},
+	{
+		name:                 "a NPM frontend app using HTTPD",
+		fixture:              "npm-httpd-javascript-frontend",
+		server:               "httpd",
+		env:                  nodeRunScripts,
+		buildpacks:           []string{"Node Engine", "NPM Install", "Node Run Script", "Apache HTTP Server"},
+		forbidden:            []string{"Nginx Server", "Caddy"},
+		environmentVariables: 8,
+		endpoint:             "/index.html",
+		body:                 "<title>React App</title>",
+		launchSBOM:           []string{"Apache HTTP Server"},
+		buildSBOM:            []string{"Node Engine", "react"},
+		caCertificates:       true,
+	},
+	{
+		name:                 "a NPM frontend app using Caddy",
+		fixture:              "npm-caddy-javascript-frontend",
+		server:               "caddy",
+		env:                  nodeRunScripts,
+		buildpacks:           []string{"Node Engine", "NPM Install", "Node Run Script", "Caddy"},
+		forbidden:            []string{"Nginx Server", "Apache HTTP Server"},
+		environmentVariables: 8,
+		endpoint:             "/index.html",
+		body:                 "<title>Caddy Frontend App</title>",
+		buildSBOM:            []string{"Node Engine"},
+	},
+	{
+		name:                 "a Hugo site using NGINX",
+		fixture:              "hugo-nginx",
+		server:               "nginx",
+		buildpacks:           []string{"Hugo", "Nginx Server"},
+		forbidden:            []string{"Node Engine", "Apache HTTP Server", "Caddy"},
+		environmentVariables: 6,
+		endpoint:             "/index.html",
+		body:                 "<title>Hugo App</title>",
+		launchSBOM:           []string{"Nginx Server"},
+	},
+	{
+		name:                 "a Hugo site using HTTPD",
+		fixture:              "hugo-httpd",
+		server:               "httpd",
+		buildpacks:           []string{"Hugo", "Apache HTTP Server"},
+		forbidden:            []string{"Node Engine", "Nginx Server", "Caddy"},
+		environmentVariables: 6,
+		endpoint:             "/index.html",
+		body:                 "<title>Hugo App</title>",
+		launchSBOM:           []string{"Apache HTTP Server"},
+	},
+	{
+		name:                 "a basic NGINX app",
+		fixture:              "nginx",
+		server:               "nginx",
+		buildpacks:           []string{"Nginx Server"},
+		forbidden:            []string{"Apache HTTP Server", "Caddy"},
+		environmentVariables: 5,
+		endpoint:             "/index.html",
+		body:                 "<body>Hello World!</body>",
+		launchSBOM:           []string{"Nginx Server"},
+		caCertificates:       true,
+	},
+	{
+		name:                 "a basic HTTPD app",
+		fixture:              "httpd",
+		server:               "httpd",
+		buildpacks:           []string{"Apache HTTP Server"},
+		forbidden:            []string{"Nginx Server", "Caddy"},
+		environmentVariables: 5,
+		endpoint:             "/index.html",
+		body:                 "<body>Hello World!</body>",
+		launchSBOM:           []string{"Apache HTTP Server"},
+		caCertificates:       true,
+	},
+	{
+		name:                 "a basic Caddy app",
+		fixture:              "caddy",
+		server:               "caddy",
+		buildpacks:           []string{"Caddy"},
+		forbidden:            []string{"Nginx Server", "Apache HTTP Server"},
+		environmentVariables: 5,
+		endpoint:             "/index.html",
+		body:                 "<body>Hello World!</body>",
+		caCertificates:       true,
+	},
+}
+
+// source copies the fixture of the group below dir, swapping its nginx.conf
+// for caddyfile in the Caddy variants.
+func (g orderGroup) source(dir string) (string, error) {
+	source, err := occam.Source(filepath.Join(dir, g.fixture))
+	if err != nil {
+		return "", err
+	}
+
+	if g.caddy {
+		err = os.Remove(filepath.Join(source, "nginx.conf"))
+		if err != nil {
+			return "", err
+		}
+
+		err = os.WriteFile(filepath.Join(source, "Caddyfile"), []byte(caddyfile), 0600)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return source, nil
+}
+
+// buildEnv returns the build environment of the group with the given
+// variables added.
+func (g orderGroup) buildEnv(env map[string]string) map[string]string {
+	result := maps.Clone(g.env)
+	if result == nil {
+		result = map[string]string{}
+	}
+
+	maps.Copy(result, env)
+
+	return result
+}
+
+func testOrderGroups(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+	)
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	for _, group := range orderGroups {
+		group := group
+
+		context("when building "+group.name, func() {
+			var (
+				image     occam.Image
+				container occam.Container
+
+				name   string
+				source string
+			)
+
+			it.Before(func() {
+				var err error
+				name, err = occam.RandomName()
+				Expect(err).NotTo(HaveOccurred())
+
+				source, err = group.source("testdata")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			it.After(func() {
+				Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+				Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+				Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+				Expect(os.RemoveAll(source)).To(Succeed())
+			})
+
+			it("creates a working OCI image", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = pack.WithNoColor().Build.
+					WithBuildpacks(webServersBuildpack).
+					WithPullPolicy("never").
+					WithEnv(group.buildEnv(nil)).
+					Execute(name, source)
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				for _, buildpack := range group.buildpacks {
+					Expect(logs).To(ContainLines(ContainSubstring("Buildpack for " + buildpack)))
+				}
+
+				for _, buildpack := range slices.Concat(group.forbidden, []string{"Procfile"}) {
+					Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for " + buildpack)))
+				}
+
+				container, err = docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(container).Should(Serve(ContainSubstring(group.body)).OnPort(8080).WithEndpoint(group.endpoint))
+			})
+
+			context("when using optional utility buildpacks", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(source, "Procfile"), []byte("web: "+startCommands[group.server]), os.ModePerm)).To(Succeed())
+				})
+
+				it("creates a working OCI image and uses the Procfile start command and other utility buildpacks", func() {
+					var err error
+					var logs fmt.Stringer
+					image, logs, err = pack.WithNoColor().Build.
+						WithBuildpacks(webServersBuildpack).
+						WithPullPolicy("never").
+						WithEnv(group.buildEnv(map[string]string{
+							"BPE_SOME_VARIABLE":      "some-value",
+							"BP_IMAGE_LABELS":        "some-label=some-value",
+							"BP_LIVE_RELOAD_ENABLED": "true",
+						})).
+						Execute(name, source)
+					Expect(err).NotTo(HaveOccurred(), logs.String())
+
+					for _, buildpack := range slices.Concat(group.buildpacks, []string{"Procfile", "Environment Variables", "Image Labels", "Watchexec"}) {
+						Expect(logs).To(ContainLines(ContainSubstring("Buildpack for " + buildpack)))
+					}
+
+					for _, buildpack := range group.forbidden {
+						Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for " + buildpack)))
+					}
+
+					Expect(logs).To(ContainLines(ContainSubstring("web: " + startCommands[group.server])))
+
+					Expect(image.Buildpacks[group.environmentVariables].Key).To(Equal("paketo-buildpacks/environment-variables"))
+					Expect(image.Buildpacks[group.environmentVariables].Layers["environment-variables"].Metadata["variables"]).To(Equal(map[string]interface{}{"SOME_VARIABLE": "some-value"}))
+					Expect(image.Labels["some-label"]).To(Equal("some-value"))
+
+					container, err = docker.Container.Run.
+						WithEnv(map[string]string{"PORT": "8080"}).
+						WithPublish("8080").
+						Execute(image.ID)
+					Expect(err).NotTo(HaveOccurred())
+
+					Eventually(container).Should(Serve(ContainSubstring(group.body)).OnPort(8080).WithEndpoint(group.endpoint), func() string {
+						logs, _ := docker.Container.Logs.Execute(container.ID)
+						return logs.String()
+					})
+				})
+			})
+		})
+
+		if !group.caCertificates {
+			continue
+		}
+
+		context("when building "+group.name+" that uses CA certificates", func() {
+			var (
+				image     occam.Image
+				container occam.Container
+
+				name   string
+				source string
+				client *http.Client
+			)
+
+			it.Before(func() {
+				var err error
+				name, err = occam.RandomName()
+				Expect(err).NotTo(HaveOccurred())
+
+				source, err = occam.Source(filepath.Join("testdata", "ca_cert_apps"))
+				Expect(err).NotTo(HaveOccurred())
+
+				// The HTTPD fixtures read the key pair of the server from certs/
+				keyPair := filepath.Join(source, group.fixture)
+				if group.server == "httpd" {
+					keyPair = filepath.Join(keyPair, "certs")
+				}
+
+				client, err = generateCertificates(
+					filepath.Join(keyPair, "cert.pem"),
+					filepath.Join(keyPair, "key.pem"),
+					filepath.Join(source, "binding", "ca.pem"),
+				)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			it.After(func() {
+				Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+				Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+				Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+				Expect(os.RemoveAll(source)).To(Succeed())
+			})
+
+			it("builds a working OCI image with the given CA certificate added to the trust store", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = pack.WithNoColor().Build.
+					WithBuildpacks(webServersBuildpack).
+					WithPullPolicy("never").
+					WithEnv(group.buildEnv(nil)).
+					Execute(name, filepath.Join(source, group.fixture))
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				for _, buildpack := range slices.Concat(group.buildpacks, []string{"CA Certificates"}) {
+					Expect(logs).To(ContainLines(ContainSubstring("Buildpack for " + buildpack)))
+				}
+
+				container, err = docker.Container.Run.
+					WithEnv(map[string]string{
+						"PORT":                 "8080",
+						"SERVICE_BINDING_ROOT": "/bindings",
+					}).
+					WithPublish("8080").
+					WithVolumes(fmt.Sprintf("%s:/bindings/ca-certificates", filepath.Join(source, "binding"))).
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(func() string {
+					cLogs, err := docker.Container.Logs.Execute(container.ID)
+					Expect(err).NotTo(HaveOccurred())
+					return cLogs.String()
+				}).Should(
+					ContainSubstring("Added 1 additional CA certificate(s) to system truststore"),
+				)
+
+				request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("https://localhost:%s%s", container.HostPort("8080"), group.endpoint), nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				var response *http.Response
+				Eventually(func() error {
+					var err error
+					response, err = client.Do(request)
+					return err
+				}).Should(BeNil())
+				defer func() { Expect(response.Body.Close()).To(Succeed()) }()
+
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+				content, err := io.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(ContainSubstring(group.body))
+			})
+		})
+	}
+}
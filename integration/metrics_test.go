@@ -0,0 +1,134 @@
+package integration_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testMetrics(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+	)
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	for _, server := range []struct {
+		name    string
+		fixture string
+		counter string
+	}{
+		{name: "NGINX", fixture: "nginx", counter: "nginx_http_requests_total"},
+		{name: "HTTPD", fixture: "httpd", counter: "apache_accesses_total"},
+	} {
+		server := server
+
+		context("when exposing the metrics of "+server.name, func() {
+			var (
+				image     occam.Image
+				container occam.Container
+
+				name   string
+				source string
+			)
+
+			it.Before(func() {
+				var err error
+				name, err = occam.RandomName()
+				Expect(err).NotTo(HaveOccurred())
+
+				source, err = occam.Source(filepath.Join("testdata", server.fixture))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			it.After(func() {
+				Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+				Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+				Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+				Expect(os.RemoveAll(source)).To(Succeed())
+			})
+
+			it("counts the requests served by the web server", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = pack.WithNoColor().Build.
+					WithBuildpacks(webServersBuildpack).
+					WithPullPolicy("never").
+					WithEnv(map[string]string{"BP_WEB_SERVER_METRICS": "true"}).
+					Execute(name, source)
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Web Server Configuration")))
+				Expect(logs).To(ContainLines(ContainSubstring("Added the metrics process, which runs the web process and serves Prometheus metrics next to it on $METRICS_PORT (default 9113)")))
+
+				// The metrics process runs the web server as its child, so it reaches
+				// the status page on the loopback address
+				container, err = docker.Container.Run.
+					WithEntrypoint("metrics").
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					WithPublish("9113").
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(container).Should(Serve(ContainSubstring("<body>Hello World!</body>")).OnPort(8080).WithEndpoint("/index.html"))
+
+				metricsURL := fmt.Sprintf("http://localhost:%s/metrics", container.HostPort("9113"))
+				counter := regexp.MustCompile(fmt.Sprintf(`(?m)^%s (\d+)$`, server.counter))
+
+				scrape := func() (float64, error) {
+					response, err := http.Get(metricsURL)
+					if err != nil {
+						return 0, err
+					}
+					defer response.Body.Close()
+
+					content, err := io.ReadAll(response.Body)
+					if err != nil {
+						return 0, err
+					}
+
+					match := counter.FindSubmatch(content)
+					if match == nil {
+						return 0, fmt.Errorf("no %s in %s", server.counter, content)
+					}
+
+					return strconv.ParseFloat(string(match[1]), 64)
+				}
+
+				var before float64
+				Eventually(func() error {
+					before, err = scrape()
+					return err
+				}).Should(Succeed())
+
+				for range 3 {
+					response, err := http.Get(fmt.Sprintf("http://localhost:%s/index.html", container.HostPort("8080")))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(response.Body.Close()).To(Succeed())
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+				}
+
+				Eventually(scrape).Should(BeNumerically(">=", before+3))
+			})
+		})
+	}
+}
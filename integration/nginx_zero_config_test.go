@@ -0,0 +1,158 @@
+package integration_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testNginxZeroConfig(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+	)
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	context("when building a frontend without any web server configuration", func() {
+		var (
+			image     occam.Image
+			container occam.Container
+
+			name   string
+			source string
+		)
+
+		it.Before(func() {
+			var err error
+			name, err = occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+
+			source, err = occam.Source(filepath.Join("testdata", "npm-zero-config-javascript-frontend"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+			Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		it("generates an NGINX configuration that serves the build output", func() {
+			var err error
+			var logs fmt.Stringer
+			image, logs, err = pack.WithNoColor().Build.
+				WithBuildpacks(webServersBuildpack).
+				WithPullPolicy("never").
+				WithEnv(map[string]string{"BP_NODE_RUN_SCRIPTS": "build"}).
+				Execute(name, source)
+			Expect(err).NotTo(HaveOccurred(), logs.String())
+
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Node Run Script")))
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Nginx Zero Config")))
+			Expect(logs).To(ContainLines(ContainSubstring("Generating nginx.conf")))
+			Expect(logs).To(ContainLines(ContainSubstring("Serving build (build/index.html was found)")))
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Nginx Server")))
+
+			Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for Apache HTTP Server")))
+
+			container, err = docker.Container.Run.
+				WithEnv(map[string]string{"PORT": "8080"}).
+				WithPublish("8080").
+				Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(container).Should(Serve(ContainSubstring("<title>Frontend App</title>")).OnPort(8080).WithEndpoint("/index.html"))
+			Eventually(container).Should(Serve(ContainSubstring("<title>Frontend App</title>")).OnPort(8080).WithEndpoint("/some/client/route"))
+		})
+
+		context("when BP_WEB_SERVER is set to nginx", func() {
+			it("leaves the configuration to the nginx buildpack", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = pack.WithNoColor().Build.
+					WithBuildpacks(webServersBuildpack).
+					WithPullPolicy("never").
+					WithEnv(map[string]string{
+						"BP_NODE_RUN_SCRIPTS": "build",
+						"BP_WEB_SERVER":       "nginx",
+						"BP_WEB_SERVER_ROOT":  "build",
+					}).
+					Execute(name, source)
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Nginx Server")))
+				Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for Nginx Zero Config")))
+
+				container, err = docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(container).Should(Serve(ContainSubstring("<title>Frontend App</title>")).OnPort(8080).WithEndpoint("/index.html"))
+			})
+		})
+	})
+
+	context("when building a frontend that ships its own nginx.conf", func() {
+		var (
+			image     occam.Image
+			container occam.Container
+
+			name   string
+			source string
+		)
+
+		it.Before(func() {
+			var err error
+			name, err = occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+
+			source, err = occam.Source(filepath.Join("testdata", "npm-nginx-javascript-frontend"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+			Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		it("uses the app's configuration", func() {
+			var err error
+			var logs fmt.Stringer
+			image, logs, err = pack.WithNoColor().Build.
+				WithBuildpacks(webServersBuildpack).
+				WithPullPolicy("never").
+				WithEnv(map[string]string{"BP_NODE_RUN_SCRIPTS": "build"}).
+				Execute(name, source)
+			Expect(err).NotTo(HaveOccurred(), logs.String())
+
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Nginx Server")))
+			Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for Nginx Zero Config")))
+
+			container, err = docker.Container.Run.
+				WithEnv(map[string]string{"PORT": "8080"}).
+				WithPublish("8080").
+				Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(container).Should(Serve(ContainSubstring("<title>React App</title>")).OnPort(8080).WithEndpoint("/index.html"))
+		})
+	})
+}
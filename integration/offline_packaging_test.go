@@ -0,0 +1,137 @@
+package integration_test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/paketo-buildpacks/web-servers/internal/manifest"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+// TestOfflinePackagingIntegration packages the composite from locally saved
+// buildpackages inside a network namespace with no interfaces, then builds an
+// app with the result. It is a separate top-level test because package.sh
+// recreates the build directory that the main suite packages into.
+func TestOfflinePackagingIntegration(t *testing.T) {
+	SetDefaultEventuallyTimeout(10 * time.Second)
+
+	suite := spec.New("Offline Packaging", spec.Report(report.Terminal{}))
+	suite("Offline Packaging", testOfflinePackaging)
+	suite.Run(t)
+}
+
+func testOfflinePackaging(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+
+		image     occam.Image
+		container occam.Container
+
+		name         string
+		source       string
+		dependencies string
+		output       string
+	)
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+
+		var err error
+		name, err = occam.RandomName()
+		Expect(err).NotTo(HaveOccurred())
+
+		source, err = occam.Source(filepath.Join("testdata", "nginx"))
+		Expect(err).NotTo(HaveOccurred())
+
+		dependencies = t.TempDir()
+		output = filepath.Join(t.TempDir(), "buildpackage.cnb")
+
+		// Everything that needs the network happens up front: saving each
+		// component buildpackage as an OCI layout archive and installing the
+		// packaging tools.
+		m, err := manifest.Load("..")
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, dependency := range m.Package.Dependencies {
+			ref, ok := strings.CutPrefix(dependency.URI, "docker://")
+			if !ok {
+				continue
+			}
+
+			out, err := exec.Command("docker", "pull", ref).CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(out))
+
+			archive := filepath.Join(dependencies, strings.NewReplacer("/", "_", ":", "_").Replace(ref)+".tar")
+			out, err = exec.Command("docker", "save", "--output", archive, ref).CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(out))
+		}
+
+		out, err := exec.Command("bash", "-c", `
+			source ../scripts/.util/print.sh
+			source ../scripts/.util/tools.sh
+			util::tools::jam::install --directory ../.bin
+			util::tools::pack::install --directory ../.bin
+			util::tools::yj::install --directory ../.bin
+		`).CombinedOutput()
+		Expect(err).NotTo(HaveOccurred(), string(out))
+	})
+
+	it.After(func() {
+		Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+		Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+		Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+		Expect(os.RemoveAll(source)).To(Succeed())
+	})
+
+	it("packages the buildpack without network access", func() {
+		current, err := user.Current()
+		Expect(err).NotTo(HaveOccurred())
+
+		// Creating a network namespace needs root, so drop back to the
+		// current user inside it. The Docker socket stays reachable because
+		// it is a Unix socket.
+		cmd := exec.Command("sudo", "--preserve-env", "unshare", "--net", "--",
+			"sudo", "--preserve-env", "--user", current.Username,
+			"env", "PATH="+os.Getenv("PATH"), "GOPROXY=off",
+			"bash", "../scripts/package.sh",
+			"--version", "1.2.3",
+			"--dependencies", dependencies,
+			"--output", output,
+		)
+		out, err := cmd.CombinedOutput()
+		Expect(err).NotTo(HaveOccurred(), string(out))
+		Expect(string(out)).To(ContainSubstring("Staging dependencies from %s", dependencies))
+
+		var logs fmt.Stringer
+		image, logs, err = pack.WithNoColor().Build.
+			WithBuildpacks(output).
+			WithPullPolicy("never").
+			Execute(name, source)
+		Expect(err).NotTo(HaveOccurred(), logs.String())
+
+		Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Nginx Server")))
+
+		container, err = docker.Container.Run.
+			WithEnv(map[string]string{"PORT": "8080"}).
+			WithPublish("8080").
+			Execute(image.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(container).Should(Serve(ContainSubstring("<body>Hello World!</body>")).OnPort(8080).WithEndpoint("/index.html"))
+	})
+}
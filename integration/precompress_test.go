@@ -0,0 +1,137 @@
+package integration_test
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testPrecompress(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+	)
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	for _, server := range []struct {
+		name      string
+		fixture   string
+		buildpack string
+		root      string
+		title     string
+	}{
+		{name: "a frontend with NGINX", fixture: "npm-zero-config-javascript-frontend", buildpack: "Buildpack for Nginx Server", root: "build", title: "Frontend App"},
+		{name: "a frontend with HTTPD", fixture: "npm-httpd-javascript-frontend", buildpack: "Buildpack for Apache HTTP Server", root: "build", title: "React App"},
+		{name: "a static site with NGINX", fixture: "nginx", buildpack: "Buildpack for Nginx Server", root: "public", title: "NGINX App"},
+		{name: "a static site with HTTPD", fixture: "httpd", buildpack: "Buildpack for Apache HTTP Server", root: "htdocs", title: "HTTPD App"},
+	} {
+		server := server
+
+		context("when serving "+server.name+" precompressed", func() {
+			var (
+				image     occam.Image
+				container occam.Container
+
+				name   string
+				source string
+			)
+
+			it.Before(func() {
+				var err error
+				name, err = occam.RandomName()
+				Expect(err).NotTo(HaveOccurred())
+
+				source, err = occam.Source(filepath.Join("testdata", server.fixture))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			it.After(func() {
+				Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+				Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+				Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+				Expect(os.RemoveAll(source)).To(Succeed())
+			})
+
+			it("serves the precompressed siblings", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = pack.WithNoColor().Build.
+					WithBuildpacks(webServersBuildpack).
+					WithPullPolicy("never").
+					WithEnv(map[string]string{
+						"BP_NODE_RUN_SCRIPTS":       "build",
+						"BP_WEB_SERVER_PRECOMPRESS": "gzip,br",
+					}).
+					Execute(name, source)
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Web Server Configuration")))
+				Expect(logs).To(ContainLines(ContainSubstring("Precompressing text assets in " + server.root)))
+				Expect(logs).To(ContainLines(ContainSubstring(server.buildpack)))
+
+				container, err = docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(container).Should(Serve(ContainSubstring("<title>" + server.title + "</title>")).OnPort(8080).WithEndpoint("/index.html"))
+
+				// Setting Accept-Encoding stops the client from decompressing
+				// the response itself
+				get := func(encoding string) *http.Response {
+					request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://localhost:%s/index.html", container.HostPort("8080")), nil)
+					Expect(err).NotTo(HaveOccurred())
+					request.Header.Set("Accept-Encoding", encoding)
+
+					response, err := http.DefaultClient.Do(request)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+					return response
+				}
+
+				response := get("br")
+				defer response.Body.Close()
+
+				Expect(response.Header.Get("Content-Encoding")).To(Equal("br"))
+				Expect(response.Header.Get("Content-Type")).To(HavePrefix("text/html"))
+				Expect(response.Header.Values("Vary")).To(ContainElement(ContainSubstring("Accept-Encoding")))
+
+				content, err := io.ReadAll(brotli.NewReader(response.Body))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(ContainSubstring("<title>" + server.title + "</title>"))
+
+				response = get("gzip")
+				defer response.Body.Close()
+
+				Expect(response.Header.Get("Content-Encoding")).To(Equal("gzip"))
+				Expect(response.Header.Get("Content-Type")).To(HavePrefix("text/html"))
+
+				reader, err := gzip.NewReader(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+				content, err = io.ReadAll(reader)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(ContainSubstring("<title>" + server.title + "</title>"))
+			})
+		})
+	}
+}
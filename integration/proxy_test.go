@@ -0,0 +1,116 @@
+package integration_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testProxy(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+	)
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	for _, server := range []struct {
+		name    string
+		fixture string
+	}{
+		{name: "NGINX", fixture: "nginx"},
+		{name: "HTTPD", fixture: "httpd"},
+	} {
+		server := server
+
+		context("when forwarding a path to a backend with "+server.name, func() {
+			var (
+				backendImage     occam.Image
+				backendContainer occam.Container
+				image            occam.Image
+				container        occam.Container
+
+				backendName string
+				name        string
+				source      string
+			)
+
+			it.Before(func() {
+				var err error
+				backendName, err = occam.RandomName()
+				Expect(err).NotTo(HaveOccurred())
+
+				name, err = occam.RandomName()
+				Expect(err).NotTo(HaveOccurred())
+
+				source, err = occam.Source(filepath.Join("testdata", server.fixture))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			it.After(func() {
+				Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+				Expect(docker.Container.Remove.Execute(backendContainer.ID)).To(Succeed())
+				Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+				Expect(docker.Image.Remove.Execute(backendImage.ID)).To(Succeed())
+				Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+				Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(backendName))).To(Succeed())
+				Expect(os.RemoveAll(source)).To(Succeed())
+			})
+
+			it("serves the backend's responses below the path", func() {
+				// The backend is a stub that serves the NGINX fixture
+				backendSource, err := occam.Source(filepath.Join("testdata", "nginx"))
+				Expect(err).NotTo(HaveOccurred())
+				defer os.RemoveAll(backendSource)
+
+				var logs fmt.Stringer
+				backendImage, logs, err = pack.WithNoColor().Build.
+					WithBuildpacks(webServersBuildpack).
+					WithPullPolicy("never").
+					Execute(backendName, backendSource)
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				backendContainer, err = docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					Execute(backendImage.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				backend := backendContainer.IPAddresses["bridge"]
+				Expect(backend).NotTo(BeEmpty())
+
+				image, logs, err = pack.WithNoColor().Build.
+					WithBuildpacks(webServersBuildpack).
+					WithPullPolicy("never").
+					WithEnv(map[string]string{"BP_WEB_SERVER_PROXY": fmt.Sprintf("/api=http://%s:8080/", backend)}).
+					Execute(name, source)
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Web Server Configuration")))
+				Expect(logs).To(ContainLines(ContainSubstring("Forwarding proxy routes")))
+				Expect(logs).To(ContainLines(ContainSubstring(fmt.Sprintf("/api -> http://%s:8080/", backend))))
+
+				container, err = docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(container).Should(Serve(ContainSubstring("<body>Hello World!</body>")).OnPort(8080).WithEndpoint("/index.html"))
+				Eventually(container).Should(Serve(ContainSubstring("<body>Hello World!</body>")).OnPort(8080).WithEndpoint("/api/index.html"))
+			})
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package integration_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+// layer names a layer of a buildpack, as in the image metadata.
+type layer struct {
+	buildpack string
+	name      string
+}
+
+// reused is the line that the buildpack prints when it reuses the layer from
+// the cache.
+func (l layer) reused() string {
+	return fmt.Sprintf("Reusing cached layer /layers/%s/%s", strings.ReplaceAll(l.buildpack, "/", "_"), l.name)
+}
+
+func testRebuild(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+	)
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	for _, app := range []struct {
+		name    string
+		fixture string
+		env     map[string]string
+		body    string
+
+		// lockfile is the file whose change reinstalls the modules layer
+		lockfile string
+		modules  layer
+
+		// cached are the layers that a rebuild of the same source reuses, and
+		// launch those of them that the image contains
+		cached []layer
+		launch []layer
+	}{
+		{
+			name:     "a NPM frontend app using NGINX",
+			fixture:  "npm-nginx-javascript-frontend",
+			env:      nodeRunScripts,
+			body:     "<title>React App</title>",
+			lockfile: "package-lock.json",
+			modules:  layer{buildpack: "paketo-buildpacks/npm-install", name: "build-modules"},
+			cached: []layer{
+				{buildpack: "paketo-buildpacks/node-engine", name: "node"},
+				{buildpack: "paketo-buildpacks/npm-install", name: "build-modules"},
+				{buildpack: "paketo-buildpacks/nginx", name: "nginx"},
+			},
+			launch: []layer{
+				{buildpack: "paketo-buildpacks/nginx", name: "nginx"},
+			},
+		},
+		{
+			name:     "a Yarn frontend app using HTTPD",
+			fixture:  "yarn-httpd-javascript-frontend",
+			env:      nodeRunScripts,
+			body:     "<title>React App</title>",
+			lockfile: "yarn.lock",
+			modules:  layer{buildpack: "paketo-buildpacks/yarn-install", name: "build-modules"},
+			cached: []layer{
+				{buildpack: "paketo-buildpacks/node-engine", name: "node"},
+				{buildpack: "paketo-buildpacks/yarn", name: "yarn"},
+				{buildpack: "paketo-buildpacks/yarn-install", name: "build-modules"},
+				{buildpack: "paketo-buildpacks/httpd", name: "httpd"},
+			},
+			launch: []layer{
+				{buildpack: "paketo-buildpacks/httpd", name: "httpd"},
+			},
+		},
+	} {
+		app := app
+
+		context("when rebuilding "+app.name, func() {
+			var (
+				images    map[string]struct{}
+				container occam.Container
+
+				name   string
+				source string
+			)
+
+			it.Before(func() {
+				var err error
+				name, err = occam.RandomName()
+				Expect(err).NotTo(HaveOccurred())
+
+				source, err = occam.Source(filepath.Join("testdata", app.fixture))
+				Expect(err).NotTo(HaveOccurred())
+
+				images = map[string]struct{}{}
+			})
+
+			it.After(func() {
+				if container.ID != "" {
+					Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+				}
+
+				// An unchanged rebuild can produce the same image
+				for id := range images {
+					Expect(docker.Image.Remove.Execute(id)).To(Succeed())
+				}
+				Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+				Expect(os.RemoveAll(source)).To(Succeed())
+			})
+
+			build := func() (occam.Image, fmt.Stringer) {
+				image, logs, err := pack.WithNoColor().Build.
+					WithBuildpacks(webServersBuildpack).
+					WithPullPolicy("never").
+					WithEnv(app.env).
+					Execute(name, source)
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				images[image.ID] = struct{}{}
+
+				return image, logs
+			}
+
+			it("reuses the cached layers of unchanged dependencies", func() {
+				first, _ := build()
+				second, logs := build()
+
+				for _, cached := range app.cached {
+					Expect(logs).To(ContainLines(ContainSubstring(cached.reused())))
+				}
+
+				for _, launch := range app.launch {
+					digest := func(image occam.Image) string {
+						for _, buildpack := range image.Buildpacks {
+							if buildpack.Key == launch.buildpack {
+								return buildpack.Layers[launch.name].SHA
+							}
+						}
+
+						return ""
+					}
+
+					Expect(digest(first)).NotTo(BeEmpty(), fmt.Sprintf("no %s layer of %s in the image", launch.name, launch.buildpack))
+					Expect(digest(second)).To(Equal(digest(first)))
+				}
+
+				var err error
+				container, err = docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					Execute(second.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(container).Should(Serve(ContainSubstring(app.body)).OnPort(8080).WithEndpoint("/index.html"))
+			})
+
+			context("when the lockfile changes between builds", func() {
+				it("reinstalls the modules", func() {
+					build()
+
+					// A trailing newline changes the checksum of the lockfile
+					// without changing the dependencies
+					lockfile, err := os.OpenFile(filepath.Join(source, app.lockfile), os.O_APPEND|os.O_WRONLY, 0)
+					Expect(err).NotTo(HaveOccurred())
+					_, err = lockfile.WriteString("\n")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(lockfile.Close()).To(Succeed())
+
+					_, logs := build()
+
+					Expect(logs).NotTo(ContainLines(ContainSubstring(app.modules.reused())))
+					Expect(logs).To(ContainLines(ContainSubstring("Executing build environment install process")))
+
+					// The other dependencies are unchanged
+					Expect(logs).To(ContainLines(ContainSubstring(layer{buildpack: "paketo-buildpacks/node-engine", name: "node"}.reused())))
+				})
+			})
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package integration_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testRedirects(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+
+		client *http.Client
+	)
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+
+		client = &http.Client{
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+	})
+
+	for _, server := range []struct {
+		name      string
+		fixture   string
+		buildpack string
+		title     string
+	}{
+		{name: "NGINX", fixture: "npm-zero-config-javascript-frontend", buildpack: "Buildpack for Nginx Server", title: "Frontend App"},
+		{name: "HTTPD", fixture: "npm-httpd-javascript-frontend", buildpack: "Buildpack for Apache HTTP Server", title: "React App"},
+	} {
+		server := server
+
+		context("when serving a frontend with _redirects and _headers with "+server.name, func() {
+			var (
+				image     occam.Image
+				container occam.Container
+
+				name   string
+				source string
+			)
+
+			it.Before(func() {
+				var err error
+				name, err = occam.RandomName()
+				Expect(err).NotTo(HaveOccurred())
+
+				source, err = occam.Source(filepath.Join("testdata", server.fixture))
+				Expect(err).NotTo(HaveOccurred())
+
+				// The build copies public/ into the build output
+				Expect(os.WriteFile(filepath.Join(source, "public", "_redirects"), []byte(`# Moved pages
+/old-page    /index.html   301
+/blog/:slug  /posts/:slug  302
+`), 0644)).To(Succeed())
+
+				Expect(os.WriteFile(filepath.Join(source, "public", "_headers"), []byte(`/*
+  X-Frame-Options: DENY
+  X-Custom-Header: some-value
+`), 0644)).To(Succeed())
+			})
+
+			it.After(func() {
+				Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+				Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+				Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+				Expect(os.RemoveAll(source)).To(Succeed())
+			})
+
+			it("serves the redirects and headers", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = pack.WithNoColor().Build.
+					WithBuildpacks(webServersBuildpack).
+					WithPullPolicy("never").
+					WithEnv(map[string]string{"BP_NODE_RUN_SCRIPTS": "build"}).
+					Execute(name, source)
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Redirects and Headers")))
+				Expect(logs).To(ContainLines(ContainSubstring("2 redirect rules, 1 header rules")))
+				Expect(logs).To(ContainLines(ContainSubstring(server.buildpack)))
+
+				container, err = docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(container).Should(Serve(ContainSubstring("<title>" + server.title + "</title>")).OnPort(8080).WithEndpoint("/index.html"))
+
+				get := func(path string) *http.Response {
+					response, err := client.Get(fmt.Sprintf("http://localhost:%s%s", container.HostPort("8080"), path))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(response.Body.Close()).To(Succeed())
+					return response
+				}
+
+				response := get("/old-page")
+				Expect(response.StatusCode).To(Equal(http.StatusMovedPermanently))
+				Expect(response.Header.Get("Location")).To(HaveSuffix("/index.html"))
+
+				response = get("/blog/hello-world")
+				Expect(response.StatusCode).To(Equal(http.StatusFound))
+				Expect(response.Header.Get("Location")).To(HaveSuffix("/posts/hello-world"))
+
+				response = get("/index.html")
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(response.Header.Get("X-Frame-Options")).To(Equal("DENY"))
+				Expect(response.Header.Get("X-Custom-Header")).To(Equal("some-value"))
+
+				response = get("/_redirects")
+				Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+	}
+}
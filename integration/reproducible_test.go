@@ -0,0 +1,148 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+// diffIDs returns the diff IDs of the layers of the image, from its config.
+func diffIDs(imageID string) ([]string, error) {
+	output, err := exec.Command("docker", "image", "inspect", "--format", "{{json .RootFS.Layers}}", imageID).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect %s: %w", imageID, err)
+	}
+
+	var layers []string
+	err = json.Unmarshal(output, &layers)
+	if err != nil {
+		return nil, err
+	}
+
+	return layers, nil
+}
+
+// layerDifferences describes each buildpack layer whose digest differs between
+// the images, or that only one of them has.
+func layerDifferences(first, second occam.Image) []string {
+	digests := func(image occam.Image) map[string]string {
+		result := map[string]string{}
+		for _, buildpack := range image.Buildpacks {
+			for name, layer := range buildpack.Layers {
+				result[fmt.Sprintf("%s/%s", buildpack.Key, name)] = layer.SHA
+			}
+		}
+
+		return result
+	}
+
+	a, b := digests(first), digests(second)
+
+	var differences []string
+	for layer, digest := range a {
+		if other, ok := b[layer]; !ok {
+			differences = append(differences, fmt.Sprintf("%s is only in the first image", layer))
+		} else if other != digest {
+			differences = append(differences, fmt.Sprintf("%s differs: %s and %s", layer, digest, other))
+		}
+	}
+
+	for layer := range b {
+		if _, ok := a[layer]; !ok {
+			differences = append(differences, fmt.Sprintf("%s is only in the second image", layer))
+		}
+	}
+
+	slices.Sort(differences)
+
+	return differences
+}
+
+func testReproducibleBuilds(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		pack   occam.Pack
+		docker occam.Docker
+	)
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	for _, fixture := range []string{"nginx", "httpd"} {
+		fixture := fixture
+
+		context("when building testdata/"+fixture+" twice with fresh caches", func() {
+			var (
+				images []occam.Image
+				names  []string
+				source string
+			)
+
+			it.Before(func() {
+				var err error
+				source, err = occam.Source(filepath.Join("testdata", fixture))
+				Expect(err).NotTo(HaveOccurred())
+
+				images, names = nil, nil
+			})
+
+			it.After(func() {
+				// Identical builds produce the same image
+				removed := map[string]bool{}
+				for _, image := range images {
+					if !removed[image.ID] {
+						Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+						removed[image.ID] = true
+					}
+				}
+
+				for _, name := range names {
+					Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+				}
+				Expect(os.RemoveAll(source)).To(Succeed())
+			})
+
+			it("produces the same image", func() {
+				// Each image name has cache volumes of its own, so neither build
+				// reuses layers of the other
+				for range 2 {
+					name, err := occam.RandomName()
+					Expect(err).NotTo(HaveOccurred())
+					names = append(names, name)
+
+					image, logs, err := pack.WithNoColor().Build.
+						WithBuildpacks(webServersBuildpack).
+						WithPullPolicy("never").
+						Execute(name, source)
+					Expect(err).NotTo(HaveOccurred(), logs.String())
+					images = append(images, image)
+				}
+
+				Expect(layerDifferences(images[0], images[1])).To(BeEmpty(), "buildpack layers differ between the builds")
+
+				first, err := diffIDs(images[0].ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				second, err := diffIDs(images[1].ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(second).To(Equal(first), "layer diff IDs differ between the builds")
+
+				// The image ID is the digest of its config
+				Expect(images[1].ID).To(Equal(images[0].ID), "config digests differ between the builds")
+			})
+		})
+	}
+}
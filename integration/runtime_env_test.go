@@ -0,0 +1,117 @@
+package integration_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testRuntimeEnv(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+	)
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	for _, server := range []struct {
+		name      string
+		fixture   string
+		buildpack string
+		title     string
+	}{
+		{name: "NGINX", fixture: "npm-zero-config-javascript-frontend", buildpack: "Buildpack for Nginx Server", title: "Frontend App"},
+		{name: "HTTPD", fixture: "npm-httpd-javascript-frontend", buildpack: "Buildpack for Apache HTTP Server", title: "React App"},
+	} {
+		server := server
+
+		context("when serving a frontend with "+server.name, func() {
+			var (
+				image  occam.Image
+				first  occam.Container
+				second occam.Container
+
+				name   string
+				source string
+			)
+
+			it.Before(func() {
+				var err error
+				name, err = occam.RandomName()
+				Expect(err).NotTo(HaveOccurred())
+
+				source, err = occam.Source(filepath.Join("testdata", server.fixture))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			it.After(func() {
+				Expect(docker.Container.Remove.Execute(first.ID)).To(Succeed())
+				Expect(docker.Container.Remove.Execute(second.ID)).To(Succeed())
+				Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+				Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+				Expect(os.RemoveAll(source)).To(Succeed())
+			})
+
+			it("renders env.js from the environment of each container", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = pack.WithNoColor().Build.
+					WithBuildpacks(webServersBuildpack).
+					WithPullPolicy("never").
+					WithEnv(map[string]string{
+						"BP_NODE_RUN_SCRIPTS":      "build",
+						"BP_RUNTIME_ENV_ALLOWLIST": "PUBLIC_*",
+					}).
+					Execute(name, source)
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Runtime Environment")))
+				Expect(logs).To(ContainLines(ContainSubstring("Writing /tmp/runtime-env/env.js at launch, served at /env.js")))
+				Expect(logs).To(ContainLines(ContainSubstring(server.buildpack)))
+
+				first, err = docker.Container.Run.
+					WithEnv(map[string]string{
+						"PORT":           "8080",
+						"PUBLIC_API_URL": "https://staging.example.com",
+						"SECRET_TOKEN":   "some-secret",
+					}).
+					WithPublish("8080").
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				second, err = docker.Container.Run.
+					WithEnv(map[string]string{
+						"PORT":           "8080",
+						"PUBLIC_API_URL": "https://production.example.com",
+					}).
+					WithPublish("8080").
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(first).Should(Serve(And(
+					ContainSubstring(`window.__ENV__ = {"PUBLIC_API_URL":"https://staging.example.com"};`),
+					Not(ContainSubstring("some-secret")),
+				)).OnPort(8080).WithEndpoint("/env.js"))
+
+				Eventually(second).Should(Serve(
+					ContainSubstring(`window.__ENV__ = {"PUBLIC_API_URL":"https://production.example.com"};`),
+				).OnPort(8080).WithEndpoint("/env.js"))
+
+				Eventually(first).Should(Serve(ContainSubstring("<title>" + server.title + "</title>")).OnPort(8080).WithEndpoint("/index.html"))
+			})
+		})
+	}
+}
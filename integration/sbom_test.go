@@ -0,0 +1,150 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+// sbomPackages returns the names of the packages listed by the CycloneDX and
+// the SPDX documents below dir, by format.
+func sbomPackages(dir string) (map[string][]string, error) {
+	packages := map[string][]string{}
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		format := map[string]string{"sbom.cdx.json": "CycloneDX", "sbom.spdx.json": "SPDX"}[entry.Name()]
+		if format == "" {
+			return nil
+		}
+
+		var document struct {
+			Components []struct {
+				Name string `json:"name"`
+			} `json:"components"`
+			Packages []struct {
+				Name string `json:"name"`
+			} `json:"packages"`
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		err = json.Unmarshal(content, &document)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+
+		for _, component := range document.Components {
+			packages[format] = append(packages[format], component.Name)
+		}
+
+		for _, pkg := range document.Packages {
+			packages[format] = append(packages[format], pkg.Name)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return packages, nil
+}
+
+func testSBOM(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		pack   occam.Pack
+		docker occam.Docker
+	)
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	for _, group := range orderGroups {
+		group := group
+
+		context("when building "+group.name, func() {
+			var (
+				image occam.Image
+
+				name     string
+				source   string
+				buildDir string
+				imageDir string
+			)
+
+			it.Before(func() {
+				var err error
+				name, err = occam.RandomName()
+				Expect(err).NotTo(HaveOccurred())
+
+				source, err = group.source("testdata")
+				Expect(err).NotTo(HaveOccurred())
+
+				buildDir = t.TempDir()
+				imageDir = t.TempDir()
+			})
+
+			it.After(func() {
+				Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+				Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+				Expect(os.RemoveAll(source)).To(Succeed())
+			})
+
+			it("lists the server, Node and the node modules in the SBOMs", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = pack.WithNoColor().Build.
+					WithBuildpacks(webServersBuildpack).
+					WithPullPolicy("never").
+					WithEnv(group.buildEnv(nil)).
+					WithSBOMOutputDir(buildDir).
+					Execute(name, source)
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				// The SBOM of the image only covers the launch layers; those of
+				// build layers, such as Node, are only written to the build output
+				output, err := exec.Command("pack", "sbom", "download", image.ID, "--output-dir", imageDir).CombinedOutput()
+				Expect(err).NotTo(HaveOccurred(), string(output))
+
+				for _, sbom := range []struct {
+					dir      string
+					packages []string
+				}{
+					{dir: filepath.Join(imageDir, "layers", "sbom", "launch"), packages: group.launchSBOM},
+					{dir: filepath.Join(buildDir, "sbom", "build"), packages: group.buildSBOM},
+				} {
+					if len(sbom.packages) == 0 {
+						continue
+					}
+
+					packages, err := sbomPackages(sbom.dir)
+					Expect(err).NotTo(HaveOccurred())
+
+					for _, format := range []string{"CycloneDX", "SPDX"} {
+						Expect(packages[format]).To(ContainElements(sbom.packages), fmt.Sprintf("%s documents below %s", format, sbom.dir))
+					}
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package integration_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testSecurityHeaders(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+	)
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	for _, server := range []struct {
+		name      string
+		fixture   string
+		buildpack string
+	}{
+		{name: "NGINX", fixture: "nginx", buildpack: "Buildpack for Nginx Server"},
+		{name: "HTTPD", fixture: "httpd", buildpack: "Buildpack for Apache HTTP Server"},
+	} {
+		server := server
+
+		context("when serving an app with security headers with "+server.name, func() {
+			var (
+				image     occam.Image
+				container occam.Container
+
+				name   string
+				source string
+			)
+
+			it.Before(func() {
+				var err error
+				name, err = occam.RandomName()
+				Expect(err).NotTo(HaveOccurred())
+
+				source, err = occam.Source(filepath.Join("testdata", server.fixture))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			it.After(func() {
+				Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+				Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+				Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+				Expect(os.RemoveAll(source)).To(Succeed())
+			})
+
+			it("sets the headers of the profile with the overrides applied", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = pack.WithNoColor().Build.
+					WithBuildpacks(webServersBuildpack).
+					WithPullPolicy("never").
+					WithEnv(map[string]string{
+						"BP_WEB_SERVER_SECURITY_HEADERS":               "strict",
+						"BP_WEB_SERVER_HEADER_CONTENT_SECURITY_POLICY": "default-src 'self' https://api.example.com",
+						"BP_WEB_SERVER_HEADER_X_ROBOTS_TAG":            "noindex",
+					}).
+					Execute(name, source)
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Web Server Configuration")))
+				Expect(logs).To(ContainLines(ContainSubstring("Setting security headers (strict profile)")))
+				Expect(logs).To(ContainLines(ContainSubstring(server.buildpack)))
+
+				container, err = docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(container).Should(Serve(ContainSubstring("<body>Hello World!</body>")).OnPort(8080).WithEndpoint("/index.html"))
+
+				for _, endpoint := range []string{"/index.html", "/does-not-exist"} {
+					response, err := http.Get(fmt.Sprintf("http://localhost:%s%s", container.HostPort("8080"), endpoint))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(response.Body.Close()).To(Succeed())
+
+					Expect(response.Header.Get("Strict-Transport-Security")).To(Equal("max-age=63072000; includeSubDomains"), endpoint)
+					Expect(response.Header.Get("X-Content-Type-Options")).To(Equal("nosniff"), endpoint)
+					Expect(response.Header.Get("X-Frame-Options")).To(Equal("DENY"), endpoint)
+					Expect(response.Header.Get("Referrer-Policy")).To(Equal("no-referrer"), endpoint)
+					Expect(response.Header.Get("Content-Security-Policy")).To(Equal("default-src 'self' https://api.example.com"), endpoint)
+					Expect(response.Header.Get("X-Robots-Tag")).To(Equal("noindex"), endpoint)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,8 @@
+const fs = require("fs");
+const path = require("path");
+
+const source = path.join(__dirname, "..", "public");
+const destination = path.join(__dirname, "..", "build");
+
+fs.rmSync(destination, { recursive: true, force: true });
+fs.cpSync(source, destination, { recursive: true });
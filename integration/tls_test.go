@@ -0,0 +1,131 @@
+package integration_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testTLS(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+	)
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	for _, server := range []struct {
+		name    string
+		fixture string
+	}{
+		{name: "NGINX", fixture: "nginx"},
+		{name: "HTTPD", fixture: "httpd"},
+		{name: "Caddy", fixture: "caddy"},
+	} {
+		server := server
+
+		context("when serving an app with TLS from a service binding with "+server.name, func() {
+			var (
+				image      occam.Image
+				containers []occam.Container
+
+				name    string
+				source  string
+				binding string
+				client  *http.Client
+			)
+
+			it.Before(func() {
+				var err error
+				name, err = occam.RandomName()
+				Expect(err).NotTo(HaveOccurred())
+
+				source, err = occam.Source(filepath.Join("testdata", server.fixture))
+				Expect(err).NotTo(HaveOccurred())
+
+				binding, err = occam.Source(filepath.Join("testdata", "tls_binding"))
+				Expect(err).NotTo(HaveOccurred())
+
+				client, err = generateCertificates(filepath.Join(binding, "tls.crt"), filepath.Join(binding, "tls.key"), "")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			it.After(func() {
+				for _, container := range containers {
+					Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+				}
+				Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+				Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+				Expect(os.RemoveAll(source)).To(Succeed())
+				Expect(os.RemoveAll(binding)).To(Succeed())
+			})
+
+			it("serves HTTPS on $PORT with the bound certificate", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = pack.WithNoColor().Build.
+					WithBuildpacks(webServersBuildpack).
+					WithPullPolicy("never").
+					WithEnv(map[string]string{"BP_WEB_SERVER_TLS": "true"}).
+					Execute(name, source)
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Web Server Configuration")))
+				Expect(logs).To(ContainLines(ContainSubstring("Enabling TLS at launch")))
+
+				container, err := docker.Container.Run.
+					WithEnv(map[string]string{
+						"PORT":                 "8080",
+						"SERVICE_BINDING_ROOT": "/bindings",
+					}).
+					WithPublish("8080").
+					WithVolumes(fmt.Sprintf("%s:/bindings/tls", binding)).
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+				containers = append(containers, container)
+
+				request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("https://localhost:%s/index.html", container.HostPort("8080")), nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				var response *http.Response
+				Eventually(func() error {
+					var err error
+					response, err = client.Do(request)
+					return err
+				}).Should(BeNil())
+				defer func() { Expect(response.Body.Close()).To(Succeed()) }()
+
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+				content, err := io.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(ContainSubstring("<body>Hello World!</body>"))
+
+				// Without the binding, the same image serves plain HTTP
+				container, err = docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+				containers = append(containers, container)
+
+				Eventually(container).Should(Serve(ContainSubstring("<body>Hello World!</body>")).OnPort(8080).WithEndpoint("/index.html"))
+			})
+		})
+	}
+}
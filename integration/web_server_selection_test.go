@@ -0,0 +1,153 @@
+package integration_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testWebServerSelection(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+	)
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	context("when building an app containing both nginx.conf and httpd.conf", func() {
+		var (
+			image     occam.Image
+			container occam.Container
+
+			name   string
+			source string
+		)
+
+		it.Before(func() {
+			var err error
+			name, err = occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+
+			source, err = occam.Source(filepath.Join("testdata", "nginx-and-httpd"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		// get returns the headers of index.html, which tell the servers apart
+		get := func() http.Header {
+			response, err := http.Get(fmt.Sprintf("http://localhost:%s/index.html", container.HostPort("8080")))
+			Expect(err).NotTo(HaveOccurred())
+			defer response.Body.Close()
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+			return response.Header
+		}
+
+		it.After(func() {
+			Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+			Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		it("selects NGINX by order precedence and explains why", func() {
+			var err error
+			var logs fmt.Stringer
+			image, logs, err = pack.WithNoColor().Build.
+				WithBuildpacks(webServersBuildpack).
+				WithPullPolicy("never").
+				Execute(name, source)
+			Expect(err).NotTo(HaveOccurred(), logs.String())
+
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Web Server Selector")))
+			Expect(logs).To(ContainLines(ContainSubstring("Chose nginx because nginx.conf was found alongside httpd.conf; set BP_WEB_SERVER to choose a different server")))
+			Expect(logs).NotTo(ContainLines(ContainSubstring("Warning: BP_WEB_SERVER")))
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Nginx Server")))
+
+			Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for Apache HTTP Server")))
+
+			container, err = docker.Container.Run.
+				WithEnv(map[string]string{"PORT": "8080"}).
+				WithPublish("8080").
+				Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(container).Should(Serve(ContainSubstring("<body>Hello World!</body>")).OnPort(8080).WithEndpoint("/index.html"))
+
+			// The app's nginx.conf is served, not its httpd.conf
+			headers := get()
+			Expect(headers.Get("Server")).To(HavePrefix("nginx"))
+			Expect(headers.Get("X-Web-Server")).To(Equal("nginx"))
+		})
+
+		context("when BP_WEB_SERVER is set to httpd", func() {
+			it("selects HTTPD", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = pack.WithNoColor().Build.
+					WithBuildpacks(webServersBuildpack).
+					WithPullPolicy("never").
+					WithEnv(map[string]string{"BP_WEB_SERVER": "httpd"}).
+					Execute(name, source)
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				Expect(logs).To(ContainLines(ContainSubstring(`Chose httpd because BP_WEB_SERVER is set to "httpd"`)))
+				Expect(logs).To(ContainLines(ContainSubstring("Warning: BP_WEB_SERVER makes the httpd buildpack generate httpd.conf in place of the one in the app")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Apache HTTP Server")))
+
+				Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for Nginx Server")))
+
+				container, err = docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Eventually(container).Should(Serve(ContainSubstring("<body>Hello World!</body>")).OnPort(8080).WithEndpoint("/index.html"))
+
+				// The configuration generated by the httpd buildpack is served
+				Expect(get().Get("Server")).To(HavePrefix("Apache"))
+			})
+		})
+
+		context("when BP_WEB_SERVER is set to nginx", func() {
+			it("selects NGINX", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = pack.WithNoColor().Build.
+					WithBuildpacks(webServersBuildpack).
+					WithPullPolicy("never").
+					WithEnv(map[string]string{"BP_WEB_SERVER": "nginx"}).
+					Execute(name, source)
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				Expect(logs).To(ContainLines(ContainSubstring(`Chose nginx because BP_WEB_SERVER is set to "nginx"`)))
+				Expect(logs).To(ContainLines(ContainSubstring("Warning: BP_WEB_SERVER makes the nginx buildpack generate nginx.conf in place of the one in the app")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Nginx Server")))
+
+				Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for Apache HTTP Server")))
+
+				container, err = docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Eventually(container).Should(Serve(ContainSubstring("<body>Hello World!</body>")).OnPort(8080).WithEndpoint("/index.html"))
+
+				// The configuration generated by the nginx buildpack is served
+				Expect(get().Get("Server")).To(HavePrefix("nginx"))
+			})
+		})
+	})
+}
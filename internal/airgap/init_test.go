@@ -0,0 +1,15 @@
+package airgap_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitAirgap(t *testing.T) {
+	suite := spec.New("airgap", spec.Report(report.Terminal{}), spec.Parallel())
+	suite("Layout", testLayout)
+	suite("Stage", testStage)
+	suite.Run(t)
+}
@@ -0,0 +1,181 @@
+package airgap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/paketo-buildpacks/web-servers/internal/manifest"
+	"github.com/paketo-buildpacks/web-servers/internal/ocilayout"
+)
+
+// MetadataLabel is the image label holding the ID and version of the
+// buildpack contained in a buildpackage.
+const MetadataLabel = "io.buildpacks.buildpackage.metadata"
+
+// Artifact is a buildpackage image found in a local dependency source.
+type Artifact struct {
+	ID      string
+	Version string
+
+	// Path is the layout directory or archive that contains the image.
+	Path string
+
+	image image
+}
+
+func key(id, version string) string {
+	return fmt.Sprintf("%s@%s", id, version)
+}
+
+// Scan finds the buildpackages built for target in dir. The directory may
+// itself be an OCI image layout, or contain layout directories and .cnb or
+// .tar archives of layouts. Images are identified by their buildpackage
+// metadata label, so files can be named freely. The result is keyed by
+// "<id>@<version>".
+func Scan(dir string, target manifest.Target) (map[string]Artifact, error) {
+	paths := []string{dir}
+	if _, err := os.Stat(filepath.Join(dir, ocispec.ImageIndexFile)); errors.Is(err, fs.ErrNotExist) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		paths = nil
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+			switch {
+			case entry.IsDir():
+				if _, err := os.Stat(filepath.Join(path, ocispec.ImageIndexFile)); err == nil {
+					paths = append(paths, path)
+				}
+			case strings.HasSuffix(entry.Name(), ".cnb"), strings.HasSuffix(entry.Name(), ".tar"):
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	artifacts := map[string]Artifact{}
+	for _, path := range paths {
+		layout, err := ocilayout.Open(path)
+		if err != nil {
+			return nil, err
+		}
+
+		found, err := images(layout, target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", path, err)
+		}
+
+		for _, image := range found {
+			if _, ok := artifacts[key(image.ID, image.Version)]; ok {
+				continue
+			}
+
+			artifacts[key(image.ID, image.Version)] = Artifact{
+				ID:      image.ID,
+				Version: image.Version,
+				Path:    path,
+				image:   image,
+			}
+		}
+	}
+
+	return artifacts, nil
+}
+
+// image is a single-platform buildpackage image found in a layout.
+type image struct {
+	ID         string
+	Version    string
+	Descriptor ocispec.Descriptor
+	Manifest   ocispec.Manifest
+}
+
+// images walks the index of a layout, descending into nested indexes, and
+// returns every buildpackage image built for the target platform.
+func images(layout fs.FS, target manifest.Target) ([]image, error) {
+	var index ocispec.Index
+	err := ocilayout.ReadJSON(layout, ocispec.ImageIndexFile, &index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ocispec.ImageIndexFile, err)
+	}
+
+	return walk(layout, index, target)
+}
+
+func walk(layout fs.FS, index ocispec.Index, target manifest.Target) ([]image, error) {
+	var found []image
+	for _, descriptor := range index.Manifests {
+		if descriptor.Platform != nil && (descriptor.Platform.OS != target.OS || descriptor.Platform.Architecture != target.Arch) {
+			continue
+		}
+
+		switch descriptor.MediaType {
+		case ocispec.MediaTypeImageIndex, "application/vnd.docker.distribution.manifest.list.v2+json":
+			var nested ocispec.Index
+			err := ocilayout.ReadJSON(layout, ocilayout.BlobPath(descriptor.Digest), &nested)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read index %s: %w", descriptor.Digest, err)
+			}
+
+			images, err := walk(layout, nested, target)
+			if err != nil {
+				return nil, err
+			}
+
+			found = append(found, images...)
+
+		case ocispec.MediaTypeImageManifest, "application/vnd.docker.distribution.manifest.v2+json":
+			var m ocispec.Manifest
+			err := ocilayout.ReadJSON(layout, ocilayout.BlobPath(descriptor.Digest), &m)
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					// Layouts exported from multi-platform images often only
+					// contain the blobs of the platforms that were pulled.
+					continue
+				}
+
+				return nil, fmt.Errorf("failed to read manifest %s: %w", descriptor.Digest, err)
+			}
+
+			var config ocispec.Image
+			err = ocilayout.ReadJSON(layout, ocilayout.BlobPath(m.Config.Digest), &config)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read config %s: %w", m.Config.Digest, err)
+			}
+
+			if config.OS != target.OS || config.Architecture != target.Arch {
+				continue
+			}
+
+			label, ok := config.Config.Labels[MetadataLabel]
+			if !ok {
+				continue
+			}
+
+			var metadata struct {
+				ID      string `json:"id"`
+				Version string `json:"version"`
+			}
+			err = json.Unmarshal([]byte(label), &metadata)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s label of %s: %w", MetadataLabel, descriptor.Digest, err)
+			}
+
+			found = append(found, image{
+				ID:         metadata.ID,
+				Version:    metadata.Version,
+				Descriptor: descriptor,
+				Manifest:   m,
+			})
+		}
+	}
+
+	return found, nil
+}
@@ -0,0 +1,224 @@
+package airgap_test
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/paketo-buildpacks/web-servers/internal/airgap"
+	"github.com/paketo-buildpacks/web-servers/internal/manifest"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+type buildpackage struct {
+	ID, Version, OS, Arch string
+}
+
+func writeBlob(t *testing.T, dir, mediaType string, content []byte) ocispec.Descriptor {
+	t.Helper()
+
+	d := digest.FromBytes(content)
+	path := filepath.Join(dir, "blobs", d.Algorithm().String(), d.Encoded())
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return ocispec.Descriptor{MediaType: mediaType, Digest: d, Size: int64(len(content))}
+}
+
+func writeJSON(t *testing.T, dir, mediaType string, v interface{}) ocispec.Descriptor {
+	t.Helper()
+
+	content, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return writeBlob(t, dir, mediaType, content)
+}
+
+// writeLayout writes an OCI image layout containing the given buildpackages
+// to dir. Multiple buildpackages are listed in a nested index, the way
+// multi-platform images are exported.
+func writeLayout(t *testing.T, dir string, buildpackages ...buildpackage) {
+	t.Helper()
+
+	var descriptors []ocispec.Descriptor
+	for _, bp := range buildpackages {
+		layer := writeBlob(t, dir, ocispec.MediaTypeImageLayerGzip, []byte(fmt.Sprintf("layer of %s %s %s", bp.ID, bp.Version, bp.Arch)))
+
+		var config ocispec.Image
+		config.OS = bp.OS
+		config.Architecture = bp.Arch
+		config.Config.Labels = map[string]string{
+			airgap.MetadataLabel: fmt.Sprintf(`{"id":%q,"version":%q}`, bp.ID, bp.Version),
+		}
+
+		descriptor := writeJSON(t, dir, ocispec.MediaTypeImageManifest, ocispec.Manifest{
+			Versioned: specs.Versioned{SchemaVersion: 2},
+			MediaType: ocispec.MediaTypeImageManifest,
+			Config:    writeJSON(t, dir, ocispec.MediaTypeImageConfig, config),
+			Layers:    []ocispec.Descriptor{layer},
+		})
+		descriptor.Platform = &ocispec.Platform{OS: bp.OS, Architecture: bp.Arch}
+
+		descriptors = append(descriptors, descriptor)
+	}
+
+	index := ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: descriptors,
+	}
+
+	if len(descriptors) > 1 {
+		index.Manifests = []ocispec.Descriptor{writeJSON(t, dir, ocispec.MediaTypeImageIndex, index)}
+	}
+
+	content, err := json.Marshal(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ocispec.ImageIndexFile), content, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ocispec.ImageLayoutFile), []byte(`{"imageLayoutVersion":"1.0.0"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// archive writes the contents of dir to a tar file at path, prefixing
+// entries with "./" like `docker save` does.
+func archive(t *testing.T, dir, path string) {
+	t.Helper()
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	tw := tar.NewWriter(file)
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		err = tw.WriteHeader(&tar.Header{Name: "./" + filepath.ToSlash(rel), Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		if err != nil {
+			return err
+		}
+
+		_, err = tw.Write(content)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testLayout(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		source string
+		amd64  = manifest.Target{OS: "linux", Arch: "amd64"}
+		arm64  = manifest.Target{OS: "linux", Arch: "arm64"}
+	)
+
+	it.Before(func() {
+		source = t.TempDir()
+	})
+
+	context("Scan", func() {
+		it.Before(func() {
+			Expect(os.Mkdir(filepath.Join(source, "nginx"), os.ModePerm)).To(Succeed())
+			writeLayout(t, filepath.Join(source, "nginx"),
+				buildpackage{ID: "paketo-buildpacks/nginx", Version: "1.1.1", OS: "linux", Arch: "amd64"},
+				buildpackage{ID: "paketo-buildpacks/nginx", Version: "1.1.1", OS: "linux", Arch: "arm64"},
+			)
+
+			caddy := t.TempDir()
+			writeLayout(t, caddy, buildpackage{ID: "paketo-community/caddy", Version: "0.6.2", OS: "linux", Arch: "amd64"})
+			archive(t, caddy, filepath.Join(source, "anything.cnb"))
+
+			Expect(os.WriteFile(filepath.Join(source, "README.md"), []byte("not a layout"), 0600)).To(Succeed())
+		})
+
+		it("finds buildpackages in layout directories and archives by their metadata label", func() {
+			artifacts, err := airgap.Scan(source, amd64)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(artifacts).To(HaveLen(2))
+			Expect(artifacts).To(HaveKey("paketo-buildpacks/nginx@1.1.1"))
+			Expect(artifacts["paketo-buildpacks/nginx@1.1.1"].Path).To(Equal(filepath.Join(source, "nginx")))
+			Expect(artifacts).To(HaveKey("paketo-community/caddy@0.6.2"))
+			Expect(artifacts["paketo-community/caddy@0.6.2"].Path).To(Equal(filepath.Join(source, "anything.cnb")))
+		})
+
+		it("only returns buildpackages built for the target", func() {
+			artifacts, err := airgap.Scan(source, arm64)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(artifacts).To(HaveLen(1))
+			Expect(artifacts).To(HaveKey("paketo-buildpacks/nginx@1.1.1"))
+		})
+
+		context("when the source is itself a layout", func() {
+			it("scans it directly", func() {
+				artifacts, err := airgap.Scan(filepath.Join(source, "nginx"), amd64)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(artifacts).To(HaveKey("paketo-buildpacks/nginx@1.1.1"))
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the source does not exist", func() {
+				it("returns an error", func() {
+					_, err := airgap.Scan(filepath.Join(source, "missing"), amd64)
+					Expect(err).To(MatchError(ContainSubstring("no such file or directory")))
+				})
+			})
+
+			context("when an archive is not a layout", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(source, "broken.tar"), []byte("not a tar"), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := airgap.Scan(source, amd64)
+					Expect(err).To(MatchError(ContainSubstring(fmt.Sprintf("failed to scan %s", filepath.Join(source, "broken.tar")))))
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,154 @@
+// Package airgap stages the buildpackages a composite depends on from local
+// OCI image layouts and .cnb files, so that it can be packaged without
+// pulling anything from a registry.
+package airgap
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/paketo-buildpacks/web-servers/internal/manifest"
+	"github.com/paketo-buildpacks/web-servers/internal/ocilayout"
+)
+
+// StagingDir is the directory, relative to package.toml, that staged
+// buildpackages are written to.
+const StagingDir = "dependencies"
+
+// Stage writes every docker:// dependency of the composite to a
+// single-image .cnb under output/dependencies and returns the package.toml
+// with those dependencies pointing at the staged files. The targets are
+// narrowed to target because the staged files only contain that platform.
+func Stage(m manifest.Manifest, artifacts map[string]Artifact, output string, target manifest.Target) (manifest.Package, error) {
+	entries := map[string]manifest.GroupEntry{}
+	for _, order := range m.Buildpack.Order {
+		for _, entry := range order.Group {
+			entries[manifest.ImageURI(entry.ID, entry.Version)] = entry
+		}
+	}
+
+	err := os.MkdirAll(filepath.Join(output, StagingDir), os.ModePerm)
+	if err != nil {
+		return manifest.Package{}, err
+	}
+
+	pkg := manifest.Package{Buildpack: m.Package.Buildpack, Targets: []manifest.Target{target}}
+
+	var missing []string
+	for _, dependency := range m.Package.Dependencies {
+		if !strings.HasPrefix(dependency.URI, "docker://") {
+			pkg.Dependencies = append(pkg.Dependencies, dependency)
+			continue
+		}
+
+		entry, ok := entries[dependency.URI]
+		if !ok {
+			return manifest.Package{}, fmt.Errorf("%s is not referenced by any order in buildpack.toml", dependency.URI)
+		}
+
+		artifact, ok := artifacts[key(entry.ID, entry.Version)]
+		if !ok {
+			missing = append(missing, key(entry.ID, entry.Version))
+			continue
+		}
+
+		name := fmt.Sprintf("%s_%s.cnb", strings.ReplaceAll(entry.ID, "/", "_"), entry.Version)
+		err = write(artifact, filepath.Join(output, StagingDir, name), target)
+		if err != nil {
+			return manifest.Package{}, fmt.Errorf("failed to stage %s: %w", key(entry.ID, entry.Version), err)
+		}
+
+		pkg.Dependencies = append(pkg.Dependencies, manifest.Dependency{URI: filepath.ToSlash(filepath.Join(StagingDir, name))})
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return manifest.Package{}, fmt.Errorf("no local buildpackage found for %s/%s: %s", target.OS, target.Arch, strings.Join(missing, ", "))
+	}
+
+	return pkg, nil
+}
+
+// write copies a single image into a new layout archive, verifying the
+// digest of every blob on the way.
+func write(artifact Artifact, path string, target manifest.Target) error {
+	layout, err := ocilayout.Open(artifact.Path)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	tw := tar.NewWriter(file)
+
+	descriptor := artifact.image.Descriptor
+	descriptor.Platform = &ocispec.Platform{OS: target.OS, Architecture: target.Arch}
+
+	index, err := json.Marshal(ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: []ocispec.Descriptor{descriptor},
+	})
+	if err != nil {
+		return err
+	}
+
+	layoutVersion, err := json.Marshal(ocispec.ImageLayout{Version: ocispec.ImageLayoutVersion})
+	if err != nil {
+		return err
+	}
+
+	for name, content := range map[string][]byte{
+		ocispec.ImageLayoutFile: layoutVersion,
+		ocispec.ImageIndexFile:  index,
+	} {
+		err = tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		if err != nil {
+			return err
+		}
+
+		_, err = tw.Write(content)
+		if err != nil {
+			return err
+		}
+	}
+
+	blobs := append([]ocispec.Descriptor{artifact.image.Descriptor, artifact.image.Manifest.Config}, artifact.image.Manifest.Layers...)
+
+	written := map[digest.Digest]bool{}
+	for _, blob := range blobs {
+		if written[blob.Digest] {
+			continue
+		}
+		written[blob.Digest] = true
+
+		err = tw.WriteHeader(&tar.Header{Name: ocilayout.BlobPath(blob.Digest), Mode: 0644, Size: blob.Size, Typeflag: tar.TypeReg})
+		if err != nil {
+			return err
+		}
+
+		err = ocilayout.CopyBlob(tw, layout, blob)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tw.Close()
+	if err != nil {
+		return err
+	}
+
+	return file.Close()
+}
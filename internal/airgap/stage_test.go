@@ -0,0 +1,145 @@
+package airgap_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/web-servers/internal/airgap"
+	"github.com/paketo-buildpacks/web-servers/internal/manifest"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testStage(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		source    string
+		output    string
+		m         manifest.Manifest
+		artifacts map[string]airgap.Artifact
+		target    = manifest.Target{OS: "linux", Arch: "amd64"}
+	)
+
+	it.Before(func() {
+		source = t.TempDir()
+		output = t.TempDir()
+
+		Expect(os.Mkdir(filepath.Join(source, "nginx"), os.ModePerm)).To(Succeed())
+		writeLayout(t, filepath.Join(source, "nginx"),
+			buildpackage{ID: "paketo-buildpacks/nginx", Version: "1.1.1", OS: "linux", Arch: "amd64"},
+			buildpackage{ID: "paketo-buildpacks/nginx", Version: "1.1.1", OS: "linux", Arch: "arm64"},
+		)
+
+		caddy := t.TempDir()
+		writeLayout(t, caddy, buildpackage{ID: "paketo-community/caddy", Version: "0.6.2", OS: "linux", Arch: "amd64"})
+		archive(t, caddy, filepath.Join(source, "caddy.cnb"))
+
+		m.Buildpack.Order = []manifest.Order{
+			{Group: []manifest.GroupEntry{
+				{ID: "some-org/some-selector", Version: "0.1.0"},
+				{ID: "paketo-buildpacks/nginx", Version: "1.1.1"},
+			}},
+			{Group: []manifest.GroupEntry{
+				{ID: "some-org/some-selector", Version: "0.1.0"},
+				{ID: "paketo-community/caddy", Version: "0.6.2"},
+			}},
+		}
+		m.Package.Buildpack.URI = "build/buildpack.tgz"
+		m.Package.Dependencies = []manifest.Dependency{
+			{URI: "build/some-selector.tgz"},
+			{URI: "docker://docker.io/paketobuildpacks/nginx:1.1.1"},
+			{URI: "docker://docker.io/paketocommunity/caddy:0.6.2"},
+		}
+		m.Package.Targets = []manifest.Target{
+			{OS: "linux", Arch: "amd64"},
+			{OS: "linux", Arch: "arm64"},
+		}
+
+		var err error
+		artifacts, err = airgap.Scan(source, target)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it("stages every registry dependency and rewrites package.toml to use them", func() {
+		pkg, err := airgap.Stage(m, artifacts, output, target)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(pkg.Buildpack.URI).To(Equal("build/buildpack.tgz"))
+		Expect(pkg.Dependencies).To(Equal([]manifest.Dependency{
+			{URI: "build/some-selector.tgz"},
+			{URI: "dependencies/paketo-buildpacks_nginx_1.1.1.cnb"},
+			{URI: "dependencies/paketo-community_caddy_0.6.2.cnb"},
+		}))
+		Expect(pkg.Targets).To(Equal([]manifest.Target{target}))
+
+		for _, dependency := range pkg.Dependencies[1:] {
+			Expect(filepath.Join(output, dependency.URI)).To(BeARegularFile())
+		}
+	})
+
+	it("stages single-image layouts that can be scanned again", func() {
+		_, err := airgap.Stage(m, artifacts, output, target)
+		Expect(err).NotTo(HaveOccurred())
+
+		staged, err := airgap.Scan(filepath.Join(output, airgap.StagingDir), target)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(staged).To(HaveLen(2))
+		Expect(staged).To(HaveKey("paketo-buildpacks/nginx@1.1.1"))
+		Expect(staged).To(HaveKey("paketo-community/caddy@0.6.2"))
+
+		other, err := airgap.Scan(filepath.Join(output, airgap.StagingDir), manifest.Target{OS: "linux", Arch: "arm64"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(other).To(BeEmpty())
+	})
+
+	context("failure cases", func() {
+		context("when a dependency has no local buildpackage", func() {
+			it.Before(func() {
+				delete(artifacts, "paketo-buildpacks/nginx@1.1.1")
+				delete(artifacts, "paketo-community/caddy@0.6.2")
+			})
+
+			it("returns an error naming every missing buildpackage", func() {
+				_, err := airgap.Stage(m, artifacts, output, target)
+				Expect(err).To(MatchError("no local buildpackage found for linux/amd64: paketo-buildpacks/nginx@1.1.1, paketo-community/caddy@0.6.2"))
+			})
+		})
+
+		context("when a dependency is not referenced by buildpack.toml", func() {
+			it.Before(func() {
+				m.Package.Dependencies = append(m.Package.Dependencies, manifest.Dependency{URI: "docker://docker.io/paketobuildpacks/httpd:1.0.18"})
+			})
+
+			it("returns an error", func() {
+				_, err := airgap.Stage(m, artifacts, output, target)
+				Expect(err).To(MatchError("docker://docker.io/paketobuildpacks/httpd:1.0.18 is not referenced by any order in buildpack.toml"))
+			})
+		})
+
+		context("when a blob does not match its digest", func() {
+			it.Before(func() {
+				nginx := artifacts["paketo-buildpacks/nginx@1.1.1"]
+				paths, err := filepath.Glob(filepath.Join(nginx.Path, "blobs", "sha256", "*"))
+				Expect(err).NotTo(HaveOccurred())
+
+				for _, path := range paths {
+					content, err := os.ReadFile(path)
+					Expect(err).NotTo(HaveOccurred())
+
+					if string(content[:5]) == "layer" {
+						content[0] = 'L'
+						Expect(os.WriteFile(path, content, 0600)).To(Succeed())
+					}
+				}
+			})
+
+			it("returns an error", func() {
+				_, err := airgap.Stage(m, artifacts, output, target)
+				Expect(err).To(MatchError(ContainSubstring("does not match its digest")))
+			})
+		})
+	})
+}
@@ -0,0 +1,68 @@
+// Package docroot locates the directory that a web server should serve for a
+// JavaScript frontend.
+package docroot
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// OutputDirectories are the directories, in order of precedence, that common
+// frontend toolchains write their build output to: build/ (Create React
+// App), dist/ (Vite, Vue CLI, Angular) and out/ (Next.js static export).
+var OutputDirectories = []string{"build", "dist", "out"}
+
+// Find returns the directory to serve, relative to workingDir, and why it was
+// chosen. BP_WEB_SERVER_ROOT takes precedence over the conventional output
+// directories.
+func Find(workingDir string) (string, string, error) {
+	if root, ok := os.LookupEnv("BP_WEB_SERVER_ROOT"); ok && root != "" {
+		if filepath.IsAbs(root) {
+			var err error
+			root, err = filepath.Rel(workingDir, root)
+			if err != nil {
+				return "", "", err
+			}
+		}
+
+		exists, err := exists(filepath.Join(workingDir, root))
+		if err != nil {
+			return "", "", err
+		}
+
+		if !exists {
+			return "", "", fmt.Errorf("BP_WEB_SERVER_ROOT is set to %q but the directory does not exist", root)
+		}
+
+		return filepath.ToSlash(root), "BP_WEB_SERVER_ROOT is set", nil
+	}
+
+	for _, dir := range OutputDirectories {
+		exists, err := exists(filepath.Join(workingDir, dir, "index.html"))
+		if err != nil {
+			return "", "", err
+		}
+
+		if exists {
+			return dir, fmt.Sprintf("%s/index.html was found", dir), nil
+		}
+	}
+
+	return "", "", fmt.Errorf("failed to find build output: none of %s contains index.html; set BP_WEB_SERVER_ROOT to the directory to serve", strings.Join(OutputDirectories, ", "))
+}
+
+func exists(path string) (bool, error) {
+	_, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
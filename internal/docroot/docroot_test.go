@@ -0,0 +1,70 @@
+package docroot_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/web-servers/internal/docroot"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testFind(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+	)
+
+	it.Before(func() {
+		workingDir = t.TempDir()
+
+		for _, dir := range []string{"dist", "out"} {
+			Expect(os.MkdirAll(filepath.Join(workingDir, dir), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(workingDir, dir, "index.html"), nil, 0600)).To(Succeed())
+		}
+	})
+
+	it("returns the first output directory that contains index.html", func() {
+		root, reason, err := docroot.Find(workingDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(root).To(Equal("dist"))
+		Expect(reason).To(Equal("dist/index.html was found"))
+	})
+
+	context("when BP_WEB_SERVER_ROOT is set", func() {
+		it.Before(func() {
+			Expect(os.MkdirAll(filepath.Join(workingDir, "public", "site"), os.ModePerm)).To(Succeed())
+			t.Setenv("BP_WEB_SERVER_ROOT", filepath.Join(workingDir, "public", "site"))
+		})
+
+		it("returns it relative to the working directory", func() {
+			root, reason, err := docroot.Find(workingDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(root).To(Equal("public/site"))
+			Expect(reason).To(Equal("BP_WEB_SERVER_ROOT is set"))
+		})
+	})
+
+	context("failure cases", func() {
+		context("when no output directory contains index.html", func() {
+			it("returns an error", func() {
+				_, _, err := docroot.Find(t.TempDir())
+				Expect(err).To(MatchError("failed to find build output: none of build, dist, out contains index.html; set BP_WEB_SERVER_ROOT to the directory to serve"))
+			})
+		})
+
+		context("when BP_WEB_SERVER_ROOT does not exist", func() {
+			it.Before(func() {
+				t.Setenv("BP_WEB_SERVER_ROOT", "missing")
+			})
+
+			it("returns an error", func() {
+				_, _, err := docroot.Find(workingDir)
+				Expect(err).To(MatchError(`BP_WEB_SERVER_ROOT is set to "missing" but the directory does not exist`))
+			})
+		})
+	})
+}
@@ -0,0 +1,14 @@
+package docroot_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitDocroot(t *testing.T) {
+	suite := spec.New("docroot", spec.Report(report.Terminal{}), spec.Sequential())
+	suite("Find", testFind)
+	suite.Run(t)
+}
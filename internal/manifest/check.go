@@ -0,0 +1,155 @@
+package manifest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ProblemKind classifies an inconsistency found by Check.
+type ProblemKind string
+
+const (
+	MissingDependency ProblemKind = "missing dependency"
+	VersionMismatch   ProblemKind = "version mismatch"
+	UnusedDependency  ProblemKind = "unused dependency"
+	MissingTarget     ProblemKind = "missing target"
+)
+
+// Problem is a single inconsistency between the composite descriptors.
+type Problem struct {
+	Kind    ProblemKind
+	Message string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s", p.Kind, p.Message)
+}
+
+// Check reports every order group entry without a matching package.toml
+// dependency, every buildpack pinned to more than one version across orders,
+// every dependency that no order uses, and every platform that package.toml
+// builds for but a first-party buildpack does not support. Problems are
+// returned grouped by kind and sorted within each kind.
+func (m Manifest) Check() []Problem {
+	firstParty := map[string]string{}
+	for uri, buildpack := range m.FirstParty {
+		firstParty[buildpack.Buildpack.ID] = uri
+	}
+
+	dependencies := map[string]bool{}
+	for _, dependency := range m.Package.Dependencies {
+		dependencies[dependency.URI] = false
+	}
+
+	versions := map[string]map[string][]int{}
+	for i, order := range m.Buildpack.Order {
+		for _, entry := range order.Group {
+			if _, ok := versions[entry.ID]; !ok {
+				versions[entry.ID] = map[string][]int{}
+			}
+			versions[entry.ID][entry.Version] = append(versions[entry.ID][entry.Version], i+1)
+		}
+	}
+
+	var missing, mismatched []Problem
+	for _, id := range sortedKeys(versions) {
+		pins := versions[id]
+
+		for _, version := range sortedKeys(pins) {
+			uri := ImageURI(id, version)
+			if local, ok := firstParty[id]; ok {
+				uri = local
+			}
+
+			if _, ok := dependencies[uri]; !ok {
+				missing = append(missing, Problem{
+					Kind:    MissingDependency,
+					Message: fmt.Sprintf("%s %s (%s) has no %q entry in package.toml", id, version, orders(pins[version]), uri),
+				})
+				continue
+			}
+
+			dependencies[uri] = true
+		}
+
+		if len(pins) > 1 {
+			var descriptions []string
+			for _, version := range sortedKeys(pins) {
+				descriptions = append(descriptions, fmt.Sprintf("%s in %s", version, orders(pins[version])))
+			}
+
+			mismatched = append(mismatched, Problem{
+				Kind:    VersionMismatch,
+				Message: fmt.Sprintf("%s is pinned to %s", id, strings.Join(descriptions, ", ")),
+			})
+		}
+	}
+
+	var unused []Problem
+	for _, uri := range sortedKeys(dependencies) {
+		if !dependencies[uri] {
+			unused = append(unused, Problem{
+				Kind:    UnusedDependency,
+				Message: fmt.Sprintf("%q is not referenced by any order in buildpack.toml", uri),
+			})
+		}
+	}
+
+	var targets []Problem
+	if len(m.Package.Targets) == 0 {
+		targets = append(targets, Problem{
+			Kind:    MissingTarget,
+			Message: "package.toml does not declare any [[targets]]",
+		})
+	}
+
+	for _, uri := range sortedKeys(m.FirstParty) {
+		buildpack := m.FirstParty[uri]
+
+		supported := map[Target]bool{}
+		for _, target := range buildpack.Targets {
+			supported[target] = true
+		}
+
+		for _, target := range m.Package.Targets {
+			if !supported[target] {
+				targets = append(targets, Problem{
+					Kind:    MissingTarget,
+					Message: fmt.Sprintf("%s does not declare target %s required by package.toml", buildpack.Buildpack.ID, target),
+				})
+			}
+		}
+	}
+
+	var problems []Problem
+	problems = append(problems, missing...)
+	problems = append(problems, mismatched...)
+	problems = append(problems, unused...)
+	problems = append(problems, targets...)
+
+	return problems
+}
+
+func orders(indices []int) string {
+	var numbers []string
+	for _, index := range indices {
+		numbers = append(numbers, fmt.Sprint(index))
+	}
+
+	if len(numbers) == 1 {
+		return "order " + numbers[0]
+	}
+
+	return "orders " + strings.Join(numbers, ", ")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
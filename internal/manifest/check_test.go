@@ -0,0 +1,81 @@
+package manifest_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/web-servers/internal/manifest"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testCheck(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("when the descriptors are consistent", func() {
+		it("reports no problems", func() {
+			m, err := manifest.Load(filepath.Join("testdata", "consistent"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(m.Check()).To(BeEmpty())
+		})
+	})
+
+	context("when the descriptors are inconsistent", func() {
+		it("reports every problem grouped by kind", func() {
+			m, err := manifest.Load(filepath.Join("testdata", "inconsistent"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(m.Check()).To(Equal([]manifest.Problem{
+				{
+					Kind:    manifest.MissingDependency,
+					Message: `paketo-buildpacks/ca-certificates 3.12.6 (order 2) has no "docker://docker.io/paketobuildpacks/ca-certificates:3.12.6" entry in package.toml`,
+				},
+				{
+					Kind:    manifest.MissingDependency,
+					Message: `paketo-buildpacks/httpd 1.0.18 (order 2) has no "docker://docker.io/paketobuildpacks/httpd:1.0.18" entry in package.toml`,
+				},
+				{
+					Kind:    manifest.VersionMismatch,
+					Message: "paketo-buildpacks/ca-certificates is pinned to 3.12.6 in order 2, 3.12.7 in orders 1, 3",
+				},
+				{
+					Kind:    manifest.UnusedDependency,
+					Message: `"docker://docker.io/paketobuildpacks/httpd:1.0.17" is not referenced by any order in buildpack.toml`,
+				},
+				{
+					Kind:    manifest.UnusedDependency,
+					Message: `"docker://docker.io/paketobuildpacks/yarn:2.4.2" is not referenced by any order in buildpack.toml`,
+				},
+				{
+					Kind:    manifest.MissingTarget,
+					Message: "some-org/some-selector does not declare target linux/arm64 required by package.toml",
+				},
+			}))
+		})
+	})
+
+	context("when package.toml declares no targets", func() {
+		it("reports the missing targets", func() {
+			m, err := manifest.Load(filepath.Join("testdata", "consistent"))
+			Expect(err).NotTo(HaveOccurred())
+
+			m.Package.Targets = nil
+
+			Expect(m.Check()).To(Equal([]manifest.Problem{
+				{
+					Kind:    manifest.MissingTarget,
+					Message: "package.toml does not declare any [[targets]]",
+				},
+			}))
+		})
+	})
+
+	context("Problem", func() {
+		it("prints the kind and message", func() {
+			problem := manifest.Problem{Kind: manifest.UnusedDependency, Message: "some-message"}
+			Expect(problem.String()).To(Equal("unused dependency: some-message"))
+		})
+	})
+}
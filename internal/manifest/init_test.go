@@ -0,0 +1,15 @@
+package manifest_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitManifest(t *testing.T) {
+	suite := spec.New("manifest", spec.Report(report.Terminal{}), spec.Parallel())
+	suite("Manifest", testManifest)
+	suite("Check", testCheck)
+	suite.Run(t)
+}
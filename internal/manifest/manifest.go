@@ -0,0 +1,134 @@
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Buildpack is the subset of a buildpack.toml descriptor that describes how a
+// buildpack is composed and which platforms it supports.
+type Buildpack struct {
+	Buildpack struct {
+		ID      string `toml:"id"`
+		Version string `toml:"version"`
+	} `toml:"buildpack"`
+	Order   []Order  `toml:"order"`
+	Targets []Target `toml:"targets"`
+}
+
+// Order is a single [[order]] block of a composite buildpack.
+type Order struct {
+	Group []GroupEntry `toml:"group"`
+}
+
+// GroupEntry is a buildpack pinned within an [[order.group]].
+type GroupEntry struct {
+	ID       string `toml:"id"`
+	Version  string `toml:"version"`
+	Optional bool   `toml:"optional,omitempty"`
+}
+
+// Package is the subset of a package.toml that lists the buildpacks bundled
+// into the buildpackage and the platforms it is built for.
+type Package struct {
+	Buildpack struct {
+		URI string `toml:"uri"`
+	} `toml:"buildpack"`
+	Dependencies []Dependency `toml:"dependencies"`
+	Targets      []Target     `toml:"targets"`
+}
+
+// Encode renders the package.toml in the layout used by this repository.
+func (p Package) Encode() []byte {
+	buffer := bytes.NewBufferString("[buildpack]\n")
+	fmt.Fprintf(buffer, "  uri = %q\n", p.Buildpack.URI)
+
+	for _, dependency := range p.Dependencies {
+		fmt.Fprintf(buffer, "\n[[dependencies]]\n  uri = %q\n", dependency.URI)
+	}
+
+	for _, target := range p.Targets {
+		fmt.Fprintf(buffer, "\n[[targets]]\n  arch = %q\n  os = %q\n", target.Arch, target.OS)
+	}
+
+	return buffer.Bytes()
+}
+
+// Dependency is a [[dependencies]] entry of a package.toml.
+type Dependency struct {
+	URI string `toml:"uri"`
+}
+
+// Target is an os/arch pair from a [[targets]] entry.
+type Target struct {
+	OS   string `toml:"os"`
+	Arch string `toml:"arch"`
+}
+
+func (t Target) String() string {
+	return fmt.Sprintf("%s/%s", t.OS, t.Arch)
+}
+
+// Manifest holds the descriptors of the composite buildpack along with the
+// first-party buildpacks that are built from the same repository.
+type Manifest struct {
+	Buildpack Buildpack
+	Package   Package
+
+	// FirstParty maps the package.toml URI of each buildpack under
+	// buildpacks/ to its descriptor.
+	FirstParty map[string]Buildpack
+}
+
+// Load reads buildpack.toml, package.toml and every buildpacks/*/buildpack.toml
+// found under the given repository root.
+func Load(root string) (Manifest, error) {
+	var manifest Manifest
+
+	_, err := toml.DecodeFile(filepath.Join(root, "buildpack.toml"), &manifest.Buildpack)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("failed to parse buildpack.toml: %w", err)
+	}
+
+	_, err = toml.DecodeFile(filepath.Join(root, "package.toml"), &manifest.Package)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("failed to parse package.toml: %w", err)
+	}
+
+	paths, err := filepath.Glob(filepath.Join(root, "buildpacks", "*", "buildpack.toml"))
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	manifest.FirstParty = map[string]Buildpack{}
+	for _, path := range paths {
+		var buildpack Buildpack
+		_, err = toml.DecodeFile(path, &buildpack)
+		if err != nil {
+			return Manifest{}, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+
+		manifest.FirstParty[LocalURI(filepath.Base(filepath.Dir(path)))] = buildpack
+	}
+
+	return manifest, nil
+}
+
+// ImageURI returns the package.toml URI expected for a buildpack published
+// to Docker Hub, where the image namespace is the buildpack ID's organization
+// with dashes removed (paketo-buildpacks/nginx -> paketobuildpacks/nginx).
+func ImageURI(id, version string) string {
+	org, name, _ := strings.Cut(id, "/")
+	return fmt.Sprintf("docker://docker.io/%s/%s:%s", strings.ReplaceAll(org, "-", ""), name, version)
+}
+
+// LocalURI returns the package.toml URI of the archive that
+// scripts/package.sh produces for the first-party buildpack in
+// buildpacks/<name>.
+func LocalURI(name string) string {
+	return fmt.Sprintf("build/%s.tgz", name)
+}
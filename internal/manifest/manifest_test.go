@@ -0,0 +1,64 @@
+package manifest_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/web-servers/internal/manifest"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testManifest(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("Load", func() {
+		it("reads the composite and first-party descriptors", func() {
+			m, err := manifest.Load(filepath.Join("testdata", "consistent"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(m.Buildpack.Buildpack.ID).To(Equal("some-org/composite"))
+			Expect(m.Buildpack.Order).To(HaveLen(2))
+			Expect(m.Buildpack.Order[0].Group).To(Equal([]manifest.GroupEntry{
+				{ID: "paketo-buildpacks/ca-certificates", Version: "3.12.7", Optional: true},
+				{ID: "some-org/some-selector", Version: "0.1.0"},
+				{ID: "paketo-buildpacks/nginx", Version: "1.1.1"},
+			}))
+
+			Expect(m.Package.Buildpack.URI).To(Equal("build/buildpack.tgz"))
+			Expect(m.Package.Dependencies).To(ContainElement(manifest.Dependency{URI: "docker://docker.io/paketocommunity/caddy:0.6.2"}))
+			Expect(m.Package.Targets).To(Equal([]manifest.Target{
+				{OS: "linux", Arch: "amd64"},
+				{OS: "linux", Arch: "arm64"},
+			}))
+
+			Expect(m.FirstParty).To(HaveLen(1))
+			Expect(m.FirstParty).To(HaveKey("build/some-selector.tgz"))
+			Expect(m.FirstParty["build/some-selector.tgz"].Buildpack.ID).To(Equal("some-org/some-selector"))
+		})
+
+		context("failure cases", func() {
+			context("when buildpack.toml cannot be read", func() {
+				it("returns an error", func() {
+					_, err := manifest.Load(filepath.Join("testdata", "no-such-directory"))
+					Expect(err).To(MatchError(ContainSubstring("failed to parse buildpack.toml")))
+				})
+			})
+
+			context("when package.toml is malformed", func() {
+				it("returns an error", func() {
+					_, err := manifest.Load(filepath.Join("testdata", "malformed"))
+					Expect(err).To(MatchError(ContainSubstring("failed to parse package.toml")))
+				})
+			})
+		})
+	})
+
+	context("ImageURI", func() {
+		it("maps the buildpack organization to its Docker Hub namespace", func() {
+			Expect(manifest.ImageURI("paketo-buildpacks/nginx", "1.1.1")).To(Equal("docker://docker.io/paketobuildpacks/nginx:1.1.1"))
+			Expect(manifest.ImageURI("paketo-community/caddy", "0.6.2")).To(Equal("docker://docker.io/paketocommunity/caddy:0.6.2"))
+		})
+	})
+}
@@ -0,0 +1,57 @@
+package matrix
+
+import (
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Definition describes the composite orders as combinations of build steps
+// and web servers, wrapped in the same utility buildpacks.
+type Definition struct {
+	// Utilities are added to every order group. Buildpacks in Before and
+	// After are optional; Required buildpacks follow them and are not.
+	Utilities struct {
+		Before   []string `toml:"before"`
+		After    []string `toml:"after"`
+		Required []string `toml:"required"`
+	} `toml:"utilities"`
+
+	// BuildSteps maps a name to the buildpacks that produce the files to
+	// serve. An empty list serves the application source as-is.
+	BuildSteps map[string][]string `toml:"build-steps"`
+
+	// Servers maps a name to the buildpacks that install and configure the
+	// web server.
+	Servers map[string][]string `toml:"servers"`
+
+	// Optional lists buildpacks that are optional wherever they appear in a
+	// build step or server, such as helpers that only pass for some apps.
+	Optional []string `toml:"optional"`
+
+	// Matrix lists the sets of combinations to emit, in order. Each set emits
+	// one order per server and build step, iterating build steps fastest.
+	Matrix []Set `toml:"matrix"`
+
+	// Versions pins buildpacks that buildpack.toml does not reference yet.
+	// Pins already present in buildpack.toml always take precedence so that
+	// automated version bumps do not have to touch this file.
+	Versions map[string]string `toml:"versions"`
+}
+
+// Set is a block of build steps that are each combined with every server.
+type Set struct {
+	BuildSteps []string `toml:"build-steps"`
+	Servers    []string `toml:"servers"`
+}
+
+// Load parses a matrix definition file.
+func Load(path string) (Definition, error) {
+	var definition Definition
+	_, err := toml.DecodeFile(path, &definition)
+	if err != nil {
+		return Definition{}, fmt.Errorf("failed to parse matrix definition: %w", err)
+	}
+
+	return definition, nil
+}
@@ -0,0 +1,153 @@
+package matrix_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/web-servers/internal/manifest"
+	"github.com/paketo-buildpacks/web-servers/internal/matrix"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDefinition(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("Load", func() {
+		it("parses the matrix definition", func() {
+			definition, err := matrix.Load(filepath.Join("testdata", "repo", "matrix.toml"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(definition.Utilities.Before).To(Equal([]string{"paketo-buildpacks/ca-certificates"}))
+			Expect(definition.Utilities.After).To(BeEmpty())
+			Expect(definition.Utilities.Required).To(Equal([]string{"paketo-buildpacks/source-removal"}))
+			Expect(definition.BuildSteps).To(Equal(map[string][]string{
+				"hugo": {"paketo-community/hugo"},
+				"none": {},
+			}))
+			Expect(definition.Servers).To(HaveKeyWithValue("caddy", []string{"some-org/some-selector", "paketo-community/caddy"}))
+			Expect(definition.Matrix).To(Equal([]matrix.Set{
+				{BuildSteps: []string{"hugo", "none"}, Servers: []string{"nginx", "caddy"}},
+			}))
+			Expect(definition.Versions).To(HaveKeyWithValue("paketo-community/hugo", "0.2.4"))
+		})
+
+		context("failure cases", func() {
+			context("when the definition is malformed", func() {
+				it("returns an error", func() {
+					_, err := matrix.Load(filepath.Join("testdata", "malformed", "matrix.toml"))
+					Expect(err).To(MatchError(ContainSubstring("failed to parse matrix definition")))
+				})
+			})
+		})
+	})
+
+	context("Orders", func() {
+		var definition matrix.Definition
+
+		it.Before(func() {
+			definition = matrix.Definition{
+				BuildSteps: map[string][]string{
+					"npm":  {"some-org/node", "some-org/npm"},
+					"none": {},
+				},
+				Servers: map[string][]string{
+					"nginx": {"some-org/nginx"},
+					"httpd": {"some-org/httpd"},
+				},
+				Matrix: []matrix.Set{
+					{BuildSteps: []string{"npm", "none"}, Servers: []string{"nginx", "httpd"}},
+				},
+			}
+			definition.Utilities.Before = []string{"some-org/certs"}
+			definition.Utilities.After = []string{"some-org/procfile"}
+			definition.Utilities.Required = []string{"some-org/cleanup"}
+		})
+
+		it("emits one order per server and build step, iterating build steps fastest", func() {
+			orders, err := definition.Orders(map[string]string{
+				"some-org/node":     "1.0.0",
+				"some-org/npm":      "2.0.0",
+				"some-org/nginx":    "3.0.0",
+				"some-org/httpd":    "4.0.0",
+				"some-org/certs":    "5.0.0",
+				"some-org/procfile": "6.0.0",
+				"some-org/cleanup":  "7.0.0",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(orders).To(HaveLen(4))
+			Expect(orders[0].Group).To(Equal([]manifest.GroupEntry{
+				{ID: "some-org/certs", Version: "5.0.0", Optional: true},
+				{ID: "some-org/node", Version: "1.0.0"},
+				{ID: "some-org/npm", Version: "2.0.0"},
+				{ID: "some-org/nginx", Version: "3.0.0"},
+				{ID: "some-org/procfile", Version: "6.0.0", Optional: true},
+				{ID: "some-org/cleanup", Version: "7.0.0"},
+			}))
+			Expect(orders[1].Group).To(Equal([]manifest.GroupEntry{
+				{ID: "some-org/certs", Version: "5.0.0", Optional: true},
+				{ID: "some-org/nginx", Version: "3.0.0"},
+				{ID: "some-org/procfile", Version: "6.0.0", Optional: true},
+				{ID: "some-org/cleanup", Version: "7.0.0"},
+			}))
+			Expect(orders[2].Group[3]).To(Equal(manifest.GroupEntry{ID: "some-org/httpd", Version: "4.0.0"}))
+			Expect(orders[3].Group[1]).To(Equal(manifest.GroupEntry{ID: "some-org/httpd", Version: "4.0.0"}))
+		})
+
+		context("when a buildpack is listed as optional", func() {
+			it.Before(func() {
+				definition.Optional = []string{"some-org/nginx"}
+			})
+
+			it("marks it optional wherever it appears", func() {
+				orders, err := definition.Orders(map[string]string{
+					"some-org/node":     "1.0.0",
+					"some-org/npm":      "2.0.0",
+					"some-org/nginx":    "3.0.0",
+					"some-org/httpd":    "4.0.0",
+					"some-org/certs":    "5.0.0",
+					"some-org/procfile": "6.0.0",
+					"some-org/cleanup":  "7.0.0",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(orders[0].Group[3]).To(Equal(manifest.GroupEntry{ID: "some-org/nginx", Version: "3.0.0", Optional: true}))
+				Expect(orders[1].Group[1]).To(Equal(manifest.GroupEntry{ID: "some-org/nginx", Version: "3.0.0", Optional: true}))
+				Expect(orders[2].Group[3]).To(Equal(manifest.GroupEntry{ID: "some-org/httpd", Version: "4.0.0"}))
+			})
+		})
+
+		context("failure cases", func() {
+			context("when a set references an undefined server", func() {
+				it.Before(func() {
+					definition.Matrix[0].Servers = []string{"caddy"}
+				})
+
+				it("returns an error", func() {
+					_, err := definition.Orders(nil)
+					Expect(err).To(MatchError(`matrix references undefined server "caddy"`))
+				})
+			})
+
+			context("when a set references an undefined build step", func() {
+				it.Before(func() {
+					definition.Matrix[0].BuildSteps = []string{"yarn"}
+				})
+
+				it("returns an error", func() {
+					_, err := definition.Orders(nil)
+					Expect(err).To(MatchError(`matrix references undefined build step "yarn"`))
+				})
+			})
+
+			context("when a buildpack has no version pin", func() {
+				it("returns an error", func() {
+					_, err := definition.Orders(map[string]string{})
+					Expect(err).To(MatchError("no version pinned for some-org/certs: add it to [versions] in the matrix definition"))
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,165 @@
+package matrix
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/paketo-buildpacks/web-servers/internal/manifest"
+)
+
+// Files holds the generated contents of buildpack.toml and package.toml.
+type Files struct {
+	Buildpack []byte
+	Package   []byte
+}
+
+// Generate renders buildpack.toml and package.toml for the definition. The
+// existing files under root supply everything the definition does not own:
+// the buildpack.toml header above the first [[order]], version pins, the
+// package.toml buildpack URI and targets, and the first-party buildpacks
+// under buildpacks/.
+func Generate(root string, definition Definition) (Files, error) {
+	content, err := os.ReadFile(filepath.Join(root, "buildpack.toml"))
+	if err != nil {
+		return Files{}, err
+	}
+
+	header, _, _ := strings.Cut(string(content), "\n[[order]]\n")
+
+	existing, err := manifest.Load(root)
+	if err != nil {
+		return Files{}, err
+	}
+
+	pins, err := resolvePins(existing.Buildpack, definition.Versions)
+	if err != nil {
+		return Files{}, err
+	}
+
+	orders, err := definition.Orders(pins)
+	if err != nil {
+		return Files{}, err
+	}
+
+	local := map[string]string{}
+	for uri, buildpack := range existing.FirstParty {
+		local[buildpack.Buildpack.ID] = uri
+	}
+
+	buildpack := bytes.NewBufferString(strings.TrimRight(header, "\n") + "\n")
+	for _, order := range orders {
+		buildpack.WriteString("\n[[order]]\n")
+		for _, entry := range order.Group {
+			fmt.Fprintf(buildpack, "\n  [[order.group]]\n    id = %q\n", entry.ID)
+			if entry.Optional {
+				buildpack.WriteString("    optional = true\n")
+			}
+			fmt.Fprintf(buildpack, "    version = %q\n", entry.Version)
+		}
+	}
+
+	pkg := manifest.Package{Targets: existing.Package.Targets}
+	pkg.Buildpack.URI = existing.Package.Buildpack.URI
+
+	seen := map[string]bool{}
+	for _, order := range orders {
+		for _, entry := range order.Group {
+			if seen[entry.ID] {
+				continue
+			}
+			seen[entry.ID] = true
+
+			uri := manifest.ImageURI(entry.ID, entry.Version)
+			if path, ok := local[entry.ID]; ok {
+				uri = path
+			}
+
+			pkg.Dependencies = append(pkg.Dependencies, manifest.Dependency{URI: uri})
+		}
+	}
+
+	return Files{
+		Buildpack: buildpack.Bytes(),
+		Package:   pkg.Encode(),
+	}, nil
+}
+
+// Orders expands the definition into composite orders using the given
+// version pins.
+func (d Definition) Orders(pins map[string]string) ([]manifest.Order, error) {
+	var orders []manifest.Order
+	for _, set := range d.Matrix {
+		for _, server := range set.Servers {
+			serverBuildpacks, ok := d.Servers[server]
+			if !ok {
+				return nil, fmt.Errorf("matrix references undefined server %q", server)
+			}
+
+			for _, step := range set.BuildSteps {
+				stepBuildpacks, ok := d.BuildSteps[step]
+				if !ok {
+					return nil, fmt.Errorf("matrix references undefined build step %q", step)
+				}
+
+				var group []manifest.GroupEntry
+				add := func(ids []string, optional bool) error {
+					for _, id := range ids {
+						version, ok := pins[id]
+						if !ok {
+							return fmt.Errorf("no version pinned for %s: add it to [versions] in the matrix definition", id)
+						}
+
+						group = append(group, manifest.GroupEntry{ID: id, Version: version, Optional: optional || slices.Contains(d.Optional, id)})
+					}
+
+					return nil
+				}
+
+				for _, part := range []struct {
+					ids      []string
+					optional bool
+				}{
+					{d.Utilities.Before, true},
+					{stepBuildpacks, false},
+					{serverBuildpacks, false},
+					{d.Utilities.After, true},
+					{d.Utilities.Required, false},
+				} {
+					err := add(part.ids, part.optional)
+					if err != nil {
+						return nil, err
+					}
+				}
+
+				orders = append(orders, manifest.Order{Group: group})
+			}
+		}
+	}
+
+	return orders, nil
+}
+
+func resolvePins(buildpack manifest.Buildpack, versions map[string]string) (map[string]string, error) {
+	pins := map[string]string{}
+	for id, version := range versions {
+		pins[id] = version
+	}
+
+	pinned := map[string]string{}
+	for _, order := range buildpack.Order {
+		for _, entry := range order.Group {
+			if version, ok := pinned[entry.ID]; ok && version != entry.Version {
+				return nil, fmt.Errorf("buildpack.toml pins %s to both %s and %s: run check-manifest and fix the pins first", entry.ID, version, entry.Version)
+			}
+
+			pinned[entry.ID] = entry.Version
+			pins[entry.ID] = entry.Version
+		}
+	}
+
+	return pins, nil
+}
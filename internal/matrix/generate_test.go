@@ -0,0 +1,201 @@
+package matrix_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/web-servers/internal/matrix"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testGenerate(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		root       string
+		definition matrix.Definition
+	)
+
+	it.Before(func() {
+		root = filepath.Join("testdata", "repo")
+
+		var err error
+		definition, err = matrix.Load(filepath.Join(root, "matrix.toml"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it("renders buildpack.toml and package.toml from the definition", func() {
+		files, err := matrix.Generate(root, definition)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(string(files.Buildpack)).To(Equal(`api = "0.7"
+
+[buildpack]
+  id = "some-org/composite"
+  name = "Some Composite Buildpack"
+
+[[order]]
+
+  [[order.group]]
+    id = "paketo-buildpacks/ca-certificates"
+    optional = true
+    version = "3.12.7"
+
+  [[order.group]]
+    id = "paketo-community/hugo"
+    version = "0.2.4"
+
+  [[order.group]]
+    id = "some-org/some-selector"
+    version = "0.1.0"
+
+  [[order.group]]
+    id = "paketo-buildpacks/nginx"
+    version = "1.1.1"
+
+  [[order.group]]
+    id = "paketo-buildpacks/source-removal"
+    version = "1.0.38"
+
+[[order]]
+
+  [[order.group]]
+    id = "paketo-buildpacks/ca-certificates"
+    optional = true
+    version = "3.12.7"
+
+  [[order.group]]
+    id = "some-org/some-selector"
+    version = "0.1.0"
+
+  [[order.group]]
+    id = "paketo-buildpacks/nginx"
+    version = "1.1.1"
+
+  [[order.group]]
+    id = "paketo-buildpacks/source-removal"
+    version = "1.0.38"
+
+[[order]]
+
+  [[order.group]]
+    id = "paketo-buildpacks/ca-certificates"
+    optional = true
+    version = "3.12.7"
+
+  [[order.group]]
+    id = "paketo-community/hugo"
+    version = "0.2.4"
+
+  [[order.group]]
+    id = "some-org/some-selector"
+    version = "0.1.0"
+
+  [[order.group]]
+    id = "paketo-community/caddy"
+    version = "0.6.2"
+
+  [[order.group]]
+    id = "paketo-buildpacks/source-removal"
+    version = "1.0.38"
+
+[[order]]
+
+  [[order.group]]
+    id = "paketo-buildpacks/ca-certificates"
+    optional = true
+    version = "3.12.7"
+
+  [[order.group]]
+    id = "some-org/some-selector"
+    version = "0.1.0"
+
+  [[order.group]]
+    id = "paketo-community/caddy"
+    version = "0.6.2"
+
+  [[order.group]]
+    id = "paketo-buildpacks/source-removal"
+    version = "1.0.38"
+`))
+
+		Expect(string(files.Package)).To(Equal(`[buildpack]
+  uri = "build/buildpack.tgz"
+
+[[dependencies]]
+  uri = "docker://docker.io/paketobuildpacks/ca-certificates:3.12.7"
+
+[[dependencies]]
+  uri = "docker://docker.io/paketocommunity/hugo:0.2.4"
+
+[[dependencies]]
+  uri = "build/some-selector.tgz"
+
+[[dependencies]]
+  uri = "docker://docker.io/paketobuildpacks/nginx:1.1.1"
+
+[[dependencies]]
+  uri = "docker://docker.io/paketobuildpacks/source-removal:1.0.38"
+
+[[dependencies]]
+  uri = "docker://docker.io/paketocommunity/caddy:0.6.2"
+
+[[targets]]
+  arch = "amd64"
+  os = "linux"
+`))
+	})
+
+	it("is stable when run against its own output", func() {
+		files, err := matrix.Generate(root, definition)
+		Expect(err).NotTo(HaveOccurred())
+
+		generated := t.TempDir()
+		Expect(os.MkdirAll(filepath.Join(generated, "buildpacks", "some-selector"), os.ModePerm)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(generated, "buildpack.toml"), files.Buildpack, 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(generated, "package.toml"), files.Package, 0600)).To(Succeed())
+
+		selector, err := os.ReadFile(filepath.Join(root, "buildpacks", "some-selector", "buildpack.toml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.WriteFile(filepath.Join(generated, "buildpacks", "some-selector", "buildpack.toml"), selector, 0600)).To(Succeed())
+
+		regenerated, err := matrix.Generate(generated, definition)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(regenerated).To(Equal(files))
+	})
+
+	context("failure cases", func() {
+		context("when buildpack.toml pins a buildpack to more than one version", func() {
+			it.Before(func() {
+				root = t.TempDir()
+				Expect(os.WriteFile(filepath.Join(root, "package.toml"), nil, 0600)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(root, "buildpack.toml"), []byte(`
+[[order]]
+  [[order.group]]
+    id = "paketo-buildpacks/nginx"
+    version = "1.1.1"
+
+[[order]]
+  [[order.group]]
+    id = "paketo-buildpacks/nginx"
+    version = "1.1.2"
+`), 0600)).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := matrix.Generate(root, definition)
+				Expect(err).To(MatchError(ContainSubstring("buildpack.toml pins paketo-buildpacks/nginx to both 1.1.1 and 1.1.2")))
+			})
+		})
+
+		context("when buildpack.toml cannot be read", func() {
+			it("returns an error", func() {
+				_, err := matrix.Generate(filepath.Join("testdata", "no-such-directory"), definition)
+				Expect(err).To(MatchError(ContainSubstring("no such file or directory")))
+			})
+		})
+	})
+}
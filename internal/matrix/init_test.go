@@ -0,0 +1,15 @@
+package matrix_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitMatrix(t *testing.T) {
+	suite := spec.New("matrix", spec.Report(report.Terminal{}), spec.Parallel())
+	suite("Definition", testDefinition)
+	suite("Generate", testGenerate)
+	suite.Run(t)
+}
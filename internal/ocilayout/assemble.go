@@ -0,0 +1,172 @@
+package ocilayout
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// Assemble writes an OCI image layout to dir whose index.json references a
+// single multi-platform image index. The index lists the image from each
+// archive, which must hold exactly one image, like the per-target .cnb files
+// written by `pack buildpack package --format file`. When ref is not empty it
+// is recorded as the org.opencontainers.image.ref.name of the index.
+func Assemble(dir, ref string, archives ...string) error {
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	index := ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+	}
+
+	platforms := map[string]string{}
+	for _, archive := range archives {
+		descriptor, err := copyImage(dir, archive)
+		if err != nil {
+			return fmt.Errorf("failed to copy %s: %w", archive, err)
+		}
+
+		platform := fmt.Sprintf("%s/%s", descriptor.Platform.OS, descriptor.Platform.Architecture)
+		if other, ok := platforms[platform]; ok {
+			return fmt.Errorf("%s and %s both contain an image for %s", other, archive, platform)
+		}
+		platforms[platform] = archive
+
+		index.Manifests = append(index.Manifests, descriptor)
+	}
+
+	descriptor, err := writeJSON(dir, ocispec.MediaTypeImageIndex, index)
+	if err != nil {
+		return err
+	}
+
+	if ref != "" {
+		descriptor.Annotations = map[string]string{ocispec.AnnotationRefName: ref}
+	}
+
+	content, err := json.Marshal(ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: []ocispec.Descriptor{descriptor},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(filepath.Join(dir, ocispec.ImageIndexFile), content, 0644)
+	if err != nil {
+		return err
+	}
+
+	content, err = json.Marshal(ocispec.ImageLayout{Version: ocispec.ImageLayoutVersion})
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, ocispec.ImageLayoutFile), content, 0644)
+}
+
+// copyImage copies the blobs of the only image in archive into dir and
+// returns its descriptor with the platform read from the image config.
+func copyImage(dir, archive string) (ocispec.Descriptor, error) {
+	layout, err := Open(archive)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	var index ocispec.Index
+	err = ReadJSON(layout, ocispec.ImageIndexFile, &index)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to read %s: %w", ocispec.ImageIndexFile, err)
+	}
+
+	if len(index.Manifests) != 1 || index.Manifests[0].MediaType != ocispec.MediaTypeImageManifest {
+		return ocispec.Descriptor{}, fmt.Errorf("expected exactly one image manifest, found %d entries", len(index.Manifests))
+	}
+
+	descriptor := index.Manifests[0]
+
+	var m ocispec.Manifest
+	err = ReadJSON(layout, BlobPath(descriptor.Digest), &m)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to read manifest %s: %w", descriptor.Digest, err)
+	}
+
+	var config ocispec.Image
+	err = ReadJSON(layout, BlobPath(m.Config.Digest), &config)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to read config %s: %w", m.Config.Digest, err)
+	}
+
+	for _, blob := range append([]ocispec.Descriptor{descriptor, m.Config}, m.Layers...) {
+		err = writeBlob(dir, layout, blob)
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+	}
+
+	platform := config.Platform
+	descriptor.Platform = &platform
+	descriptor.Annotations = nil
+
+	return descriptor, nil
+}
+
+// writeBlob copies a blob into dir unless it is already present, which is
+// common for layers shared between platforms.
+func writeBlob(dir string, layout fs.FS, blob ocispec.Descriptor) error {
+	path := filepath.Join(dir, filepath.FromSlash(BlobPath(blob.Digest)))
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+
+	err := os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = CopyBlob(file, layout, blob)
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+
+	return file.Close()
+}
+
+func writeJSON(dir, mediaType string, v interface{}) (ocispec.Descriptor, error) {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	d := digest.FromBytes(content)
+	path := filepath.Join(dir, filepath.FromSlash(BlobPath(d)))
+
+	err = os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	err = os.WriteFile(path, content, 0644)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	return ocispec.Descriptor{MediaType: mediaType, Digest: d, Size: int64(len(content))}, nil
+}
@@ -0,0 +1,205 @@
+package ocilayout_test
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/paketo-buildpacks/web-servers/internal/ocilayout"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+// writeBuildpackage writes a single-image .cnb for the platform with one
+// layer per buildpack, keyed "<id>@<version>", and the matching layers label.
+func writeBuildpackage(t *testing.T, path, goos, arch string, buildpacks ...string) {
+	t.Helper()
+
+	blobs := map[string][]byte{}
+	add := func(mediaType string, content []byte) ocispec.Descriptor {
+		d := digest.FromBytes(content)
+		blobs[ocilayout.BlobPath(d)] = content
+		return ocispec.Descriptor{MediaType: mediaType, Digest: d, Size: int64(len(content))}
+	}
+	addJSON := func(mediaType string, v interface{}) ocispec.Descriptor {
+		content, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return add(mediaType, content)
+	}
+
+	var config ocispec.Image
+	config.OS = goos
+	config.Architecture = arch
+	config.RootFS.Type = "layers"
+
+	var layers []ocispec.Descriptor
+	label := map[string]map[string]map[string]string{}
+	for i, bp := range buildpacks {
+		at := strings.LastIndex(bp, "@")
+		id, version := bp[:at], bp[at+1:]
+
+		// The layers are uncompressed so the diff ID is the blob digest.
+		layer := add(ocispec.MediaTypeImageLayer, []byte(fmt.Sprintf("layer %d of %s for %s/%s", i, bp, goos, arch)))
+		layers = append(layers, layer)
+		config.RootFS.DiffIDs = append(config.RootFS.DiffIDs, layer.Digest)
+
+		if label[id] == nil {
+			label[id] = map[string]map[string]string{}
+		}
+		label[id][version] = map[string]string{"layerDiffID": layer.Digest.String()}
+	}
+
+	content, err := json.Marshal(label)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Config.Labels = map[string]string{ocilayout.LayersLabel: string(content)}
+
+	m := addJSON(ocispec.MediaTypeImageManifest, ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    addJSON(ocispec.MediaTypeImageConfig, config),
+		Layers:    layers,
+	})
+
+	index, err := json.Marshal(ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: []ocispec.Descriptor{m},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	blobs[ocispec.ImageIndexFile] = index
+	blobs[ocispec.ImageLayoutFile] = []byte(`{"imageLayoutVersion":"1.0.0"}`)
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	tw := tar.NewWriter(file)
+	for name, content := range blobs {
+		err = tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = tw.Write(content)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testAssemble(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		archives string
+		output   string
+	)
+
+	it.Before(func() {
+		archives = t.TempDir()
+		output = filepath.Join(t.TempDir(), "layout")
+
+		writeBuildpackage(t, filepath.Join(archives, "buildpackage-linux-amd64.cnb"), "linux", "amd64", "some-org/composite@1.2.3", "paketo-buildpacks/nginx@1.1.1")
+		writeBuildpackage(t, filepath.Join(archives, "buildpackage-linux-arm64.cnb"), "linux", "arm64", "some-org/composite@1.2.3", "paketo-buildpacks/nginx@1.1.1")
+	})
+
+	it("writes a layout referencing a multi-platform index", func() {
+		err := ocilayout.Assemble(output, "docker.io/some-org/composite:1.2.3",
+			filepath.Join(archives, "buildpackage-linux-amd64.cnb"),
+			filepath.Join(archives, "buildpackage-linux-arm64.cnb"),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(filepath.Join(output, ocispec.ImageLayoutFile)).To(BeARegularFile())
+
+		layout, err := ocilayout.Open(output)
+		Expect(err).NotTo(HaveOccurred())
+
+		var root ocispec.Index
+		Expect(ocilayout.ReadJSON(layout, ocispec.ImageIndexFile, &root)).To(Succeed())
+		Expect(root.Manifests).To(HaveLen(1))
+		Expect(root.Manifests[0].MediaType).To(Equal(ocispec.MediaTypeImageIndex))
+		Expect(root.Manifests[0].Annotations).To(Equal(map[string]string{ocispec.AnnotationRefName: "docker.io/some-org/composite:1.2.3"}))
+
+		var index ocispec.Index
+		Expect(ocilayout.ReadJSON(layout, ocilayout.BlobPath(root.Manifests[0].Digest), &index)).To(Succeed())
+		Expect(index.Manifests).To(HaveLen(2))
+		Expect(index.Manifests[0].Platform).To(Equal(&ocispec.Platform{OS: "linux", Architecture: "amd64"}))
+		Expect(index.Manifests[1].Platform).To(Equal(&ocispec.Platform{OS: "linux", Architecture: "arm64"}))
+
+		for _, descriptor := range index.Manifests {
+			var m ocispec.Manifest
+			Expect(ocilayout.ReadJSON(layout, ocilayout.BlobPath(descriptor.Digest), &m)).To(Succeed())
+			for _, blob := range append([]ocispec.Descriptor{m.Config}, m.Layers...) {
+				Expect(filepath.Join(output, ocilayout.BlobPath(blob.Digest))).To(BeARegularFile())
+			}
+		}
+	})
+
+	context("when no ref is given", func() {
+		it("does not annotate the index", func() {
+			err := ocilayout.Assemble(output, "", filepath.Join(archives, "buildpackage-linux-amd64.cnb"))
+			Expect(err).NotTo(HaveOccurred())
+
+			layout, err := ocilayout.Open(output)
+			Expect(err).NotTo(HaveOccurred())
+
+			var root ocispec.Index
+			Expect(ocilayout.ReadJSON(layout, ocispec.ImageIndexFile, &root)).To(Succeed())
+			Expect(root.Manifests[0].Annotations).To(BeNil())
+		})
+	})
+
+	context("failure cases", func() {
+		context("when two archives are for the same platform", func() {
+			it("returns an error", func() {
+				err := ocilayout.Assemble(output, "",
+					filepath.Join(archives, "buildpackage-linux-amd64.cnb"),
+					filepath.Join(archives, "buildpackage-linux-amd64.cnb"),
+				)
+				Expect(err).To(MatchError(ContainSubstring("both contain an image for linux/amd64")))
+			})
+		})
+
+		context("when an archive does not exist", func() {
+			it("returns an error", func() {
+				err := ocilayout.Assemble(output, "", filepath.Join(archives, "missing.cnb"))
+				Expect(err).To(MatchError(ContainSubstring("failed to copy")))
+			})
+		})
+
+		context("when an archive holds more than one image", func() {
+			it.Before(func() {
+				Expect(ocilayout.Assemble(filepath.Join(archives, "multi"), "",
+					filepath.Join(archives, "buildpackage-linux-amd64.cnb"),
+					filepath.Join(archives, "buildpackage-linux-arm64.cnb"),
+				)).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				err := ocilayout.Assemble(output, "", filepath.Join(archives, "multi"))
+				Expect(err).To(MatchError(ContainSubstring("expected exactly one image manifest")))
+			})
+		})
+	})
+}
@@ -0,0 +1,15 @@
+package ocilayout_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitOCILayout(t *testing.T) {
+	suite := spec.New("ocilayout", spec.Report(report.Terminal{}), spec.Parallel())
+	suite("Assemble", testAssemble)
+	suite("Verify", testVerify)
+	suite.Run(t)
+}
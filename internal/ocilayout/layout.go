@@ -0,0 +1,109 @@
+// Package ocilayout reads, writes and verifies OCI image layouts, the format
+// used by .cnb buildpackages and by the release artifacts of this repository.
+package ocilayout
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// Open returns the OCI image layout at path, which is either a directory or a
+// tar archive of one, such as a .cnb file or the output of `docker save`.
+func Open(path string) (fs.FS, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		return os.DirFS(path), nil
+	}
+
+	return tarLayout(path), nil
+}
+
+// tarLayout is an fs.FS over a tar archive. Every Open scans the archive from
+// the start, which keeps memory use flat for the handful of blobs that are
+// read from each buildpackage.
+type tarLayout string
+
+func (t tarLayout) Open(name string) (fs.File, error) {
+	file, err := os.Open(string(t))
+	if err != nil {
+		return nil, err
+	}
+
+	reader := tar.NewReader(file)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to read %s: %w", t, err)
+		}
+
+		if path.Clean(strings.TrimPrefix(header.Name, "./")) == name && header.Typeflag == tar.TypeReg {
+			return tarFile{Reader: reader, file: file, info: header.FileInfo()}, nil
+		}
+	}
+
+	file.Close()
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+type tarFile struct {
+	*tar.Reader
+	file *os.File
+	info fs.FileInfo
+}
+
+func (f tarFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f tarFile) Close() error               { return f.file.Close() }
+
+// BlobPath returns the path of a blob relative to the root of a layout.
+func BlobPath(d digest.Digest) string {
+	return path.Join("blobs", d.Algorithm().String(), d.Encoded())
+}
+
+// ReadJSON decodes the file at name in the layout into v.
+func ReadJSON(layout fs.FS, name string, v interface{}) error {
+	content, err := fs.ReadFile(layout, name)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(content, v)
+}
+
+// CopyBlob writes the blob described by d from the layout to w, failing if
+// its content does not match the digest and size of the descriptor.
+func CopyBlob(w io.Writer, layout fs.FS, d ocispec.Descriptor) error {
+	source, err := layout.Open(BlobPath(d.Digest))
+	if err != nil {
+		return fmt.Errorf("failed to open blob %s: %w", d.Digest, err)
+	}
+	defer source.Close()
+
+	verifier := d.Digest.Verifier()
+	_, err = io.CopyN(io.MultiWriter(w, verifier), source, d.Size)
+	if err != nil {
+		return fmt.Errorf("failed to copy blob %s: %w", d.Digest, err)
+	}
+
+	if !verifier.Verified() {
+		return fmt.Errorf("blob %s does not match its digest", d.Digest)
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package ocilayout
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/paketo-buildpacks/web-servers/internal/manifest"
+)
+
+// LayersLabel is the image label of a buildpackage that maps each buildpack
+// ID and version it contains to the diff ID of the layer holding it.
+const LayersLabel = "io.buildpacks.buildpack.layers"
+
+// Verify checks that the layout in dir holds a multi-platform index with an
+// image for every target in package.toml, and that each image carries a layer
+// for the composite and for every buildpack referenced by its orders. All
+// problems are returned joined into a single error.
+func Verify(dir string, m manifest.Manifest) error {
+	layout := os.DirFS(dir)
+
+	var root ocispec.Index
+	err := ReadJSON(layout, ocispec.ImageIndexFile, &root)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", ocispec.ImageIndexFile, err)
+	}
+
+	if len(root.Manifests) != 1 || root.Manifests[0].MediaType != ocispec.MediaTypeImageIndex {
+		return fmt.Errorf("%s must reference exactly one image index", ocispec.ImageIndexFile)
+	}
+
+	var index ocispec.Index
+	err = ReadJSON(layout, BlobPath(root.Manifests[0].Digest), &index)
+	if err != nil {
+		return fmt.Errorf("failed to read image index %s: %w", root.Manifests[0].Digest, err)
+	}
+
+	expected := map[string]map[string]bool{}
+	expect := func(id, version string) {
+		if expected[id] == nil {
+			expected[id] = map[string]bool{}
+		}
+		expected[id][version] = true
+	}
+
+	expect(m.Buildpack.Buildpack.ID, m.Buildpack.Buildpack.Version)
+	for _, order := range m.Buildpack.Order {
+		for _, entry := range order.Group {
+			expect(entry.ID, entry.Version)
+		}
+	}
+
+	var problems []error
+
+	found := map[string]bool{}
+	for _, descriptor := range index.Manifests {
+		if descriptor.Platform == nil {
+			problems = append(problems, fmt.Errorf("image %s has no platform", descriptor.Digest))
+			continue
+		}
+
+		platform := manifest.Target{OS: descriptor.Platform.OS, Arch: descriptor.Platform.Architecture}
+		found[platform.String()] = true
+
+		for _, problem := range verifyImage(layout, descriptor, expected) {
+			problems = append(problems, fmt.Errorf("%s: %w", platform, problem))
+		}
+	}
+
+	for _, target := range m.Package.Targets {
+		if !found[target.String()] {
+			problems = append(problems, fmt.Errorf("no image for target %s", target))
+		}
+	}
+
+	return errors.Join(problems...)
+}
+
+func verifyImage(layout fs.FS, descriptor ocispec.Descriptor, expected map[string]map[string]bool) []error {
+	var m ocispec.Manifest
+	err := ReadJSON(layout, BlobPath(descriptor.Digest), &m)
+	if err != nil {
+		return []error{fmt.Errorf("failed to read manifest %s: %w", descriptor.Digest, err)}
+	}
+
+	var config ocispec.Image
+	err = ReadJSON(layout, BlobPath(m.Config.Digest), &config)
+	if err != nil {
+		return []error{fmt.Errorf("failed to read config %s: %w", m.Config.Digest, err)}
+	}
+
+	var problems []error
+	if config.OS != descriptor.Platform.OS || config.Architecture != descriptor.Platform.Architecture {
+		problems = append(problems, fmt.Errorf("config is for %s/%s", config.OS, config.Architecture))
+	}
+
+	if len(m.Layers) != len(config.RootFS.DiffIDs) {
+		problems = append(problems, fmt.Errorf("manifest has %d layers but config has %d diff IDs", len(m.Layers), len(config.RootFS.DiffIDs)))
+	}
+
+	for _, layer := range m.Layers {
+		info, err := fs.Stat(layout, BlobPath(layer.Digest))
+		if err != nil {
+			problems = append(problems, fmt.Errorf("layer %s is missing", layer.Digest))
+			continue
+		}
+
+		if info.Size() != layer.Size {
+			problems = append(problems, fmt.Errorf("layer %s is %d bytes, expected %d", layer.Digest, info.Size(), layer.Size))
+		}
+	}
+
+	var layers map[string]map[string]struct {
+		LayerDiffID string `json:"layerDiffID"`
+	}
+	err = json.Unmarshal([]byte(config.Config.Labels[LayersLabel]), &layers)
+	if err != nil {
+		return append(problems, fmt.Errorf("failed to parse %s label: %w", LayersLabel, err))
+	}
+
+	diffIDs := map[string]bool{}
+	for _, diffID := range config.RootFS.DiffIDs {
+		diffIDs[diffID.String()] = true
+	}
+
+	var ids []string
+	for id := range expected {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		var versions []string
+		for version := range expected[id] {
+			versions = append(versions, version)
+		}
+		sort.Strings(versions)
+
+		for _, version := range versions {
+			layer, ok := layers[id][version]
+			if !ok && version == "" {
+				// The composite is only versioned once it has been packaged, so
+				// accept any version when checking an unpackaged buildpack.toml.
+				for _, l := range layers[id] {
+					layer, ok = l, true
+					break
+				}
+			}
+
+			if !ok {
+				problems = append(problems, fmt.Errorf("missing layer for %s@%s", id, version))
+				continue
+			}
+
+			if !diffIDs[layer.LayerDiffID] {
+				problems = append(problems, fmt.Errorf("layer %s for %s@%s is not in the image", layer.LayerDiffID, id, version))
+			}
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,120 @@
+package ocilayout_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/web-servers/internal/manifest"
+	"github.com/paketo-buildpacks/web-servers/internal/ocilayout"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testVerify(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		archives string
+		output   string
+		m        manifest.Manifest
+	)
+
+	it.Before(func() {
+		archives = t.TempDir()
+		output = filepath.Join(t.TempDir(), "layout")
+
+		m.Buildpack.Buildpack.ID = "some-org/composite"
+		m.Buildpack.Buildpack.Version = "1.2.3"
+		m.Buildpack.Order = []manifest.Order{
+			{Group: []manifest.GroupEntry{{ID: "paketo-buildpacks/nginx", Version: "1.1.1"}}},
+			{Group: []manifest.GroupEntry{{ID: "paketo-community/caddy", Version: "0.6.2"}}},
+		}
+		m.Package.Targets = []manifest.Target{
+			{OS: "linux", Arch: "amd64"},
+			{OS: "linux", Arch: "arm64"},
+		}
+
+		writeBuildpackage(t, filepath.Join(archives, "amd64.cnb"), "linux", "amd64", "some-org/composite@1.2.3", "paketo-buildpacks/nginx@1.1.1", "paketo-community/caddy@0.6.2")
+		writeBuildpackage(t, filepath.Join(archives, "arm64.cnb"), "linux", "arm64", "some-org/composite@1.2.3", "paketo-buildpacks/nginx@1.1.1", "paketo-community/caddy@0.6.2")
+	})
+
+	it("accepts a layout with every buildpack for every target", func() {
+		Expect(ocilayout.Assemble(output, "", filepath.Join(archives, "amd64.cnb"), filepath.Join(archives, "arm64.cnb"))).To(Succeed())
+		Expect(ocilayout.Verify(output, m)).To(Succeed())
+	})
+
+	context("when the composite has not been versioned yet", func() {
+		it.Before(func() {
+			m.Buildpack.Buildpack.Version = ""
+		})
+
+		it("accepts any version of the composite", func() {
+			Expect(ocilayout.Assemble(output, "", filepath.Join(archives, "amd64.cnb"), filepath.Join(archives, "arm64.cnb"))).To(Succeed())
+			Expect(ocilayout.Verify(output, m)).To(Succeed())
+		})
+	})
+
+	context("when a target is missing", func() {
+		it("reports it", func() {
+			Expect(ocilayout.Assemble(output, "", filepath.Join(archives, "amd64.cnb"))).To(Succeed())
+			Expect(ocilayout.Verify(output, m)).To(MatchError("no image for target linux/arm64"))
+		})
+	})
+
+	context("when a platform is missing a buildpack layer", func() {
+		it.Before(func() {
+			writeBuildpackage(t, filepath.Join(archives, "arm64.cnb"), "linux", "arm64", "some-org/composite@1.2.3", "paketo-buildpacks/nginx@1.1.0")
+		})
+
+		it("reports every missing buildpack", func() {
+			Expect(ocilayout.Assemble(output, "", filepath.Join(archives, "amd64.cnb"), filepath.Join(archives, "arm64.cnb"))).To(Succeed())
+
+			err := ocilayout.Verify(output, m)
+			Expect(err).To(MatchError(ContainSubstring("linux/arm64: missing layer for paketo-buildpacks/nginx@1.1.1")))
+			Expect(err).To(MatchError(ContainSubstring("linux/arm64: missing layer for paketo-community/caddy@0.6.2")))
+			Expect(err).NotTo(MatchError(ContainSubstring("linux/amd64")))
+		})
+	})
+
+	context("when a layer blob is missing from the layout", func() {
+		it("reports it", func() {
+			Expect(ocilayout.Assemble(output, "", filepath.Join(archives, "amd64.cnb"), filepath.Join(archives, "arm64.cnb"))).To(Succeed())
+
+			paths, err := filepath.Glob(filepath.Join(output, "blobs", "sha256", "*"))
+			Expect(err).NotTo(HaveOccurred())
+
+			var removed int
+			for _, path := range paths {
+				content, err := os.ReadFile(path)
+				Expect(err).NotTo(HaveOccurred())
+
+				if string(content) == "layer 1 of paketo-buildpacks/nginx@1.1.1 for linux/amd64" {
+					Expect(os.Remove(path)).To(Succeed())
+					removed++
+				}
+			}
+			Expect(removed).To(Equal(1))
+
+			Expect(ocilayout.Verify(output, m)).To(MatchError(MatchRegexp(`linux/amd64: layer sha256:\w+ is missing`)))
+		})
+	})
+
+	context("failure cases", func() {
+		context("when the layout does not reference an image index", func() {
+			it("returns an error", func() {
+				Expect(os.MkdirAll(output, os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(output, "index.json"), []byte(`{"schemaVersion":2,"manifests":[]}`), 0600)).To(Succeed())
+
+				Expect(ocilayout.Verify(output, m)).To(MatchError("index.json must reference exactly one image index"))
+			})
+		})
+
+		context("when the layout does not exist", func() {
+			it("returns an error", func() {
+				Expect(ocilayout.Verify(output, m)).To(MatchError(ContainSubstring("failed to read index.json")))
+			})
+		})
+	})
+}
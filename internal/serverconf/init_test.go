@@ -0,0 +1,14 @@
+package serverconf_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitServerConf(t *testing.T) {
+	suite := spec.New("serverconf", spec.Report(report.Terminal{}), spec.Parallel())
+	suite("ServerConf", testServerConf)
+	suite.Run(t)
+}
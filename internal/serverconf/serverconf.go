@@ -0,0 +1,326 @@
+// Package serverconf manages the configuration fragments that the first-party
+// buildpacks add to the NGINX, HTTPD or Caddy configuration of an app.
+//
+// Fragments are written below Dir in the application source, one directory
+// per context they are valid in, and the server configuration includes every
+// fragment in each directory. A generated nginx.conf includes them from the
+// start; a configuration provided by the app has the includes injected.
+package serverconf
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Dir is the directory, relative to the application source, that holds the
+// fragments.
+const Dir = "web-servers"
+
+// Context is a directory of fragments that are valid in the same place in
+// the server configuration.
+type Context string
+
+const (
+	// NginxHTTP fragments are included in the http block of nginx.conf.
+	NginxHTTP Context = "nginx/http"
+
+	// NginxServer fragments are included in every server block of nginx.conf.
+	NginxServer Context = "nginx/server"
+
+	// NginxHeaders fragments only hold add_header directives. They are
+	// included in every server block, and again in each location defined by a
+	// fragment, because a location with add_header directives of its own does
+	// not inherit those of the server.
+	NginxHeaders Context = "nginx/headers"
+
+	// Httpd fragments are included at the end of httpd.conf, in the main
+	// server context.
+	Httpd Context = "httpd"
+
+	// Caddy fragments are imported at the start of every site block of the
+	// Caddyfile.
+	Caddy Context = "caddy"
+)
+
+// Contexts lists every context.
+var Contexts = []Context{NginxHTTP, NginxServer, NginxHeaders, Httpd, Caddy}
+
+// Include returns the glob, relative to the application source, that
+// includes every fragment in the context.
+func (c Context) Include() string {
+	return filepath.ToSlash(filepath.Join(Dir, string(c), "*.conf"))
+}
+
+// Write writes a fragment named name to the context, replacing any earlier
+// fragment of the same name. Names are prefixed with a number because
+// fragments are included in lexical order.
+func Write(workingDir string, context Context, name string, content []byte) error {
+	dir := filepath.Join(workingDir, Dir, string(context))
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	err = os.WriteFile(filepath.Join(dir, name+".conf"), content, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write %s fragment: %w", name, err)
+	}
+
+	return nil
+}
+
+// HttpdInclude is appended to httpd.conf. APP_ROOT is set by the httpd
+// buildpack at launch, and relative paths would be resolved against the
+// server root instead of the application source.
+var HttpdInclude = fmt.Sprintf(`IncludeOptional "${APP_ROOT}/%s"`, Httpd.Include())
+
+// CaddyImport is added to each site block of the Caddyfile. Caddy resolves
+// the glob relative to the Caddyfile and skips it when nothing matches.
+var CaddyImport = fmt.Sprintf("import %s", Caddy.Include())
+
+var (
+	caddySite    = regexp.MustCompile(`(?m)^[^\s{(&#][^\n]*\{[ \t]*\n`)
+	nginxHTTP    = regexp.MustCompile(`(?m)^([ \t]*)http\s*\{[^\n]*\n`)
+	nginxServer  = regexp.MustCompile(`(?m)^([ \t]*)server\s*\{[^\n]*\n`)
+	nginxInclude = regexp.MustCompile(`(?m)^[ \t]*include\s+([^\s;*?\[]+)\s*;`)
+	nginxListen  = regexp.MustCompile(`(?m)^[ \t]*listen\s[^;]*\{\{port\}\}[^;]*;`)
+	nginxSSL     = regexp.MustCompile(`\sssl[\s;]`)
+	nginxRoot    = regexp.MustCompile(`(?m)^[ \t]*root\s+["']?([^\s;"']+)["']?\s*;`)
+	httpdRoot    = regexp.MustCompile(`(?mi)^[ \t]*DocumentRoot\s+["']?([^\s"']+)["']?`)
+)
+
+// Inject adds the includes to the configuration of the named server in
+// workingDir, returning whether it was changed. Missing configuration, or
+// configuration that already includes the fragments, is left alone.
+func Inject(workingDir, server string) (bool, error) {
+	switch server {
+	case "nginx":
+		return injectNginx(workingDir)
+	case "httpd":
+		return injectHttpd(workingDir)
+	case "caddy":
+		return injectCaddy(workingDir)
+	default:
+		return false, fmt.Errorf("configuration fragments are not supported for %s", server)
+	}
+}
+
+// injectNginx adds the includes to nginx.conf and to the files it includes
+// from the application source, as server blocks are often kept in a file of
+// their own.
+func injectNginx(workingDir string) (bool, error) {
+	names, files, err := nginxFiles(workingDir)
+	if err != nil || len(names) == 0 {
+		return false, err
+	}
+
+	found := false
+	for _, name := range names {
+		if strings.Contains(files[name], NginxServer.Include()) {
+			return false, nil
+		}
+
+		found = found || nginxServer.MatchString(files[name])
+	}
+
+	if !found {
+		return false, errors.New("failed to add configuration fragments: no server block found in nginx.conf or the files it includes")
+	}
+
+	for _, name := range names {
+		content := nginxHTTP.ReplaceAllString(files[name], fmt.Sprintf("${0}$1  include %s;\n", NginxHTTP.Include()))
+		content = nginxServer.ReplaceAllString(content, fmt.Sprintf("${0}$1  include %s;\n$1  include %s;\n", NginxServer.Include(), NginxHeaders.Include()))
+		if content == files[name] {
+			continue
+		}
+
+		err = os.WriteFile(filepath.Join(workingDir, name), []byte(content), 0644)
+		if err != nil {
+			return false, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+
+	return true, nil
+}
+
+// ListenSSL adds the ssl parameter to the listen directives for {{port}}, the
+// port that the nginx buildpack renders at launch, in nginx.conf and the files
+// it includes from the application source. It returns whether any directive
+// listens with TLS afterwards.
+func ListenSSL(workingDir string) (bool, error) {
+	names, files, err := nginxFiles(workingDir)
+	if err != nil {
+		return false, err
+	}
+
+	found := false
+	for _, name := range names {
+		content := nginxListen.ReplaceAllStringFunc(files[name], func(directive string) string {
+			found = true
+			if nginxSSL.MatchString(directive) {
+				return directive
+			}
+
+			return strings.TrimSuffix(directive, ";") + " ssl;"
+		})
+		if content == files[name] {
+			continue
+		}
+
+		err = os.WriteFile(filepath.Join(workingDir, name), []byte(content), 0644)
+		if err != nil {
+			return false, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+
+	return found, nil
+}
+
+// Root returns the document root, relative to workingDir, that the
+// configuration of the named server provided by the app serves: the first
+// root directive in nginx.conf and the files it includes, or the
+// DocumentRoot of httpd.conf. It reports false when there is no
+// configuration, or when its root is outside the application source.
+func Root(workingDir, server string) (string, bool, error) {
+	var roots []string
+	switch server {
+	case "nginx":
+		names, files, err := nginxFiles(workingDir)
+		if err != nil {
+			return "", false, err
+		}
+
+		for _, name := range names {
+			for _, match := range nginxRoot.FindAllStringSubmatch(files[name], -1) {
+				roots = append(roots, match[1])
+			}
+		}
+	case "httpd":
+		config, err := read(workingDir, "httpd.conf")
+		if err != nil {
+			return "", false, err
+		}
+
+		for _, match := range httpdRoot.FindAllStringSubmatch(config, -1) {
+			roots = append(roots, strings.TrimPrefix(match[1], "${APP_ROOT}/"))
+		}
+	}
+
+	if len(roots) == 0 {
+		return "", false, nil
+	}
+
+	root := roots[0]
+	if filepath.IsAbs(root) {
+		var err error
+		root, err = filepath.Rel(workingDir, root)
+		if err != nil {
+			return "", false, nil
+		}
+	}
+
+	if !filepath.IsLocal(root) && root != "." {
+		return "", false, nil
+	}
+
+	return filepath.ToSlash(filepath.Clean(root)), true, nil
+}
+
+// nginxFiles returns the names and contents of nginx.conf and the files it
+// includes from the application source, or nothing when there is no
+// nginx.conf.
+func nginxFiles(workingDir string) ([]string, map[string]string, error) {
+	config, err := read(workingDir, "nginx.conf")
+	if err != nil || config == "" {
+		return nil, nil, err
+	}
+
+	files := map[string]string{"nginx.conf": config}
+	names := []string{"nginx.conf"}
+	for _, match := range nginxInclude.FindAllStringSubmatch(config, -1) {
+		name := match[1]
+		if !filepath.IsLocal(name) || slices.Contains(names, name) {
+			continue
+		}
+
+		content, err := read(workingDir, name)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		files[name] = content
+		names = append(names, name)
+	}
+
+	return names, files, nil
+}
+
+func injectHttpd(workingDir string) (bool, error) {
+	config, err := read(workingDir, "httpd.conf")
+	if err != nil || config == "" {
+		return false, err
+	}
+
+	if strings.Contains(config, HttpdInclude) {
+		return false, nil
+	}
+
+	if !strings.HasSuffix(config, "\n") {
+		config += "\n"
+	}
+
+	config += "\n" + HttpdInclude + "\n"
+
+	err = os.WriteFile(filepath.Join(workingDir, "httpd.conf"), []byte(config), 0644)
+	if err != nil {
+		return false, fmt.Errorf("failed to write httpd.conf: %w", err)
+	}
+
+	return true, nil
+}
+
+// injectCaddy imports the fragments into each site block. A global options
+// block, snippets and named routes are left alone.
+func injectCaddy(workingDir string) (bool, error) {
+	config, err := read(workingDir, "Caddyfile")
+	if err != nil || config == "" {
+		return false, err
+	}
+
+	if strings.Contains(config, CaddyImport) {
+		return false, nil
+	}
+
+	if !caddySite.MatchString(config) {
+		return false, errors.New("failed to add configuration fragments: no site block found in Caddyfile")
+	}
+
+	config = caddySite.ReplaceAllString(config, fmt.Sprintf("${0}\t%s\n", CaddyImport))
+
+	err = os.WriteFile(filepath.Join(workingDir, "Caddyfile"), []byte(config), 0644)
+	if err != nil {
+		return false, fmt.Errorf("failed to write Caddyfile: %w", err)
+	}
+
+	return true, nil
+}
+
+// read returns the contents of a configuration file, or nothing when it does
+// not exist.
+func read(workingDir, name string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(workingDir, name))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	return string(content), nil
+}
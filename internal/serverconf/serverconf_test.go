@@ -0,0 +1,333 @@
+package serverconf_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/web-servers/internal/serverconf"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testServerConf(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+	)
+
+	it.Before(func() {
+		workingDir = t.TempDir()
+	})
+
+	context("Write", func() {
+		it("writes the fragment to its context", func() {
+			Expect(serverconf.Write(workingDir, serverconf.NginxServer, "10-some-fragment", []byte("gzip on;\n"))).To(Succeed())
+
+			content, err := os.ReadFile(filepath.Join(workingDir, "web-servers", "nginx", "server", "10-some-fragment.conf"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("gzip on;\n"))
+		})
+	})
+
+	context("Inject", func() {
+		context("when the app has an nginx.conf", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "nginx.conf"), []byte(`events {}
+
+http {
+  # server { is only matched at the start of a line
+  server {
+    listen {{port}};
+  }
+
+  server {  # a second server
+    listen 8081;
+  }
+}
+`), 0600)).To(Succeed())
+			})
+
+			it("includes the fragments in the http block and every server block", func() {
+				changed, err := serverconf.Inject(workingDir, "nginx")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(changed).To(BeTrue())
+
+				content, err := os.ReadFile(filepath.Join(workingDir, "nginx.conf"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(Equal(`events {}
+
+http {
+  include web-servers/nginx/http/*.conf;
+  # server { is only matched at the start of a line
+  server {
+    include web-servers/nginx/server/*.conf;
+    include web-servers/nginx/headers/*.conf;
+    listen {{port}};
+  }
+
+  server {  # a second server
+    include web-servers/nginx/server/*.conf;
+    include web-servers/nginx/headers/*.conf;
+    listen 8081;
+  }
+}
+`))
+			})
+
+			it("only injects the includes once", func() {
+				_, err := serverconf.Inject(workingDir, "nginx")
+				Expect(err).NotTo(HaveOccurred())
+
+				before, err := os.ReadFile(filepath.Join(workingDir, "nginx.conf"))
+				Expect(err).NotTo(HaveOccurred())
+
+				changed, err := serverconf.Inject(workingDir, "nginx")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(changed).To(BeFalse())
+
+				after, err := os.ReadFile(filepath.Join(workingDir, "nginx.conf"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(after).To(Equal(before))
+			})
+		})
+
+		context("when the server block is in a file included by nginx.conf", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "nginx.conf"), []byte("http {\n  include mime.types;\n  include custom.conf;\n  include conf.d/*.conf;\n}\n"), 0600)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "custom.conf"), []byte("  server {\n    listen {{port}};\n  }\n"), 0600)).To(Succeed())
+			})
+
+			it("includes the fragments in that file", func() {
+				changed, err := serverconf.Inject(workingDir, "nginx")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(changed).To(BeTrue())
+
+				content, err := os.ReadFile(filepath.Join(workingDir, "nginx.conf"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(Equal("http {\n  include web-servers/nginx/http/*.conf;\n  include mime.types;\n  include custom.conf;\n  include conf.d/*.conf;\n}\n"))
+
+				content, err = os.ReadFile(filepath.Join(workingDir, "custom.conf"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(Equal("  server {\n    include web-servers/nginx/server/*.conf;\n    include web-servers/nginx/headers/*.conf;\n    listen {{port}};\n  }\n"))
+
+				changed, err = serverconf.Inject(workingDir, "nginx")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(changed).To(BeFalse())
+			})
+		})
+
+		context("when the app has an httpd.conf", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "httpd.conf"), []byte(`Listen "${PORT}"`), 0600)).To(Succeed())
+			})
+
+			it("includes the fragments at the end", func() {
+				changed, err := serverconf.Inject(workingDir, "httpd")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(changed).To(BeTrue())
+
+				changed, err = serverconf.Inject(workingDir, "httpd")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(changed).To(BeFalse())
+
+				content, err := os.ReadFile(filepath.Join(workingDir, "httpd.conf"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(Equal("Listen \"${PORT}\"\n\nIncludeOptional \"${APP_ROOT}/web-servers/httpd/*.conf\"\n"))
+			})
+		})
+
+		context("when the app has a Caddyfile", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "Caddyfile"), []byte(`{
+	admin off
+}
+
+(some-snippet) {
+	log
+}
+
+:{$PORT} {
+	root * public
+	file_server
+}
+`), 0600)).To(Succeed())
+			})
+
+			it("imports the fragments in every site block", func() {
+				changed, err := serverconf.Inject(workingDir, "caddy")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(changed).To(BeTrue())
+
+				changed, err = serverconf.Inject(workingDir, "caddy")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(changed).To(BeFalse())
+
+				content, err := os.ReadFile(filepath.Join(workingDir, "Caddyfile"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(Equal(`{
+	admin off
+}
+
+(some-snippet) {
+	log
+}
+
+:{$PORT} {
+	import web-servers/caddy/*.conf
+	root * public
+	file_server
+}
+`))
+			})
+		})
+
+		context("when the app has no configuration", func() {
+			it("does nothing", func() {
+				changed, err := serverconf.Inject(workingDir, "nginx")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(changed).To(BeFalse())
+				Expect(filepath.Join(workingDir, "nginx.conf")).NotTo(BeAnExistingFile())
+			})
+		})
+
+		context("failure cases", func() {
+			context("when nginx.conf has no server block", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "nginx.conf"), []byte("http {}\n"), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := serverconf.Inject(workingDir, "nginx")
+					Expect(err).To(MatchError("failed to add configuration fragments: no server block found in nginx.conf or the files it includes"))
+				})
+			})
+
+			context("when the Caddyfile has no site block", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "Caddyfile"), []byte(":{$PORT}\nfile_server\n"), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := serverconf.Inject(workingDir, "caddy")
+					Expect(err).To(MatchError("failed to add configuration fragments: no site block found in Caddyfile"))
+				})
+			})
+
+			context("when the server is not supported", func() {
+				it("returns an error", func() {
+					_, err := serverconf.Inject(workingDir, "lighttpd")
+					Expect(err).To(MatchError("configuration fragments are not supported for lighttpd"))
+				})
+			})
+		})
+	})
+
+	context("ListenSSL", func() {
+		context("when the app has an nginx.conf", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "nginx.conf"), []byte(`http {
+  include custom.conf;
+
+  server {
+    listen 8081;
+  }
+}
+`), 0600)).To(Succeed())
+
+				Expect(os.WriteFile(filepath.Join(workingDir, "custom.conf"), []byte(`server {
+  listen {{port}} default_server;
+}
+
+server {
+  listen [::]:{{port}} ssl;
+}
+`), 0600)).To(Succeed())
+			})
+
+			it("adds the ssl parameter to the listen directives for the port", func() {
+				found, err := serverconf.ListenSSL(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+
+				content, err := os.ReadFile(filepath.Join(workingDir, "nginx.conf"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(ContainSubstring("    listen 8081;\n"))
+
+				content, err = os.ReadFile(filepath.Join(workingDir, "custom.conf"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(Equal(`server {
+  listen {{port}} default_server ssl;
+}
+
+server {
+  listen [::]:{{port}} ssl;
+}
+`))
+			})
+		})
+
+		context("when nginx.conf does not listen on the port", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "nginx.conf"), []byte("http {\n  server {\n    listen 8081;\n  }\n}\n"), 0600)).To(Succeed())
+			})
+
+			it("reports it", func() {
+				found, err := serverconf.ListenSSL(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+		})
+	})
+
+	context("Root", func() {
+		context("when the app has an nginx.conf", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "nginx.conf"), []byte("http {\n  include custom.conf;\n}\n"), 0600)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "custom.conf"), []byte("server {\n  listen {{port}};\n  root public;\n}\n"), 0600)).To(Succeed())
+			})
+
+			it("returns the first root in it or the files it includes", func() {
+				root, found, err := serverconf.Root(workingDir, "nginx")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(root).To(Equal("public"))
+			})
+		})
+
+		context("when the app has an httpd.conf", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "httpd.conf"), []byte("ServerRoot \"${SERVER_ROOT}\"\nDocumentRoot \"${APP_ROOT}/htdocs\"\n"), 0600)).To(Succeed())
+			})
+
+			it("returns its DocumentRoot relative to the app", func() {
+				root, found, err := serverconf.Root(workingDir, "httpd")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(root).To(Equal("htdocs"))
+			})
+		})
+
+		context("when the root is outside the application source", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "nginx.conf"), []byte("http {\n  server {\n    root /usr/share/nginx/html;\n  }\n}\n"), 0600)).To(Succeed())
+			})
+
+			it("reports that there is none", func() {
+				_, found, err := serverconf.Root(workingDir, "nginx")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+		})
+
+		context("when the app has no configuration", func() {
+			it("reports that there is none", func() {
+				_, found, err := serverconf.Root(workingDir, "httpd")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+		})
+	})
+}
@@ -0,0 +1,14 @@
+package simulator_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitSimulator(t *testing.T) {
+	suite := spec.New("simulator", spec.Report(report.Terminal{}), spec.Parallel())
+	suite("Simulate", testSimulate)
+	suite.Run(t)
+}
@@ -0,0 +1,275 @@
+package simulator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	webserverconfig "github.com/paketo-buildpacks/web-servers/buildpacks/web-server-config"
+)
+
+// Rule approximates the detect phase of a single component buildpack.
+type Rule func(app App, group Group) (Outcome, error)
+
+// Rules maps component buildpack IDs to the local approximation of their
+// detection. They mirror the conditions documented by each buildpack rather
+// than its full build plan, which is enough to explain why a group passed.
+var Rules = map[string]Rule{
+	"paketo-buildpacks/ca-certificates": caCertificates,
+	"paketo-buildpacks/watchexec":       envEquals("BP_LIVE_RELOAD_ENABLED", "true"),
+
+	"paketo-buildpacks/node-engine":     always("provides node"),
+	"paketo-buildpacks/yarn":            always("provides yarn"),
+	"paketo-buildpacks/yarn-install":    files("package.json", "yarn.lock"),
+	"paketo-buildpacks/pnpm":            always("provides pnpm"),
+	"paketo-buildpacks/pnpm-install":    files("package.json", "pnpm-lock.yaml"),
+	"paketo-buildpacks/npm-install":     files("package.json"),
+	"paketo-buildpacks/node-run-script": all(files("package.json"), envSet("BP_NODE_RUN_SCRIPTS")),
+	"paketo-community/bun":              always("provides bun"),
+	"paketo-community/bun-install":      all(files("package.json"), anyFile("bun.lock", "bun.lockb")),
+	"paketo-community/bun-run-script":   all(files("package.json"), envSet("BP_BUN_RUN_SCRIPTS")),
+	"paketo-buildpacks/runtime-env":     envSet("BP_RUNTIME_ENV_ALLOWLIST"),
+	"paketo-community/hugo":             anyFile("hugo.toml", "hugo.yaml", "hugo.json", "config.toml", "config.yaml", "config.json"),
+
+	"paketo-buildpacks/web-server-selector": webServerSelector,
+	"paketo-buildpacks/redirects":           anyFile("_redirects", "_headers", "public/_redirects", "public/_headers", "static/_redirects", "static/_headers"),
+	"paketo-buildpacks/web-server-config":   webServerConfig(),
+	"paketo-buildpacks/nginx-config":        nginxConfig,
+	"paketo-buildpacks/nginx":               server("nginx", "nginx.conf"),
+	"paketo-buildpacks/httpd":               server("httpd", "httpd.conf"),
+	"paketo-community/caddy":                server("caddy", "Caddyfile"),
+
+	"paketo-buildpacks/procfile":              files("Procfile"),
+	"paketo-buildpacks/environment-variables": envPrefix("BPE_"),
+	"paketo-buildpacks/image-labels":          anyOf(envSet("BP_IMAGE_LABELS"), envPrefix("BP_OCI_")),
+	"paketo-buildpacks/source-removal":        always("always passes"),
+}
+
+// servers maps the BP_WEB_SERVER names understood by the web server selector
+// to the buildpack that provides each server.
+var servers = map[string]string{
+	"nginx": "paketo-buildpacks/nginx",
+	"httpd": "paketo-buildpacks/httpd",
+	"caddy": "paketo-community/caddy",
+}
+
+func always(reason string) Rule {
+	return func(App, Group) (Outcome, error) {
+		return Outcome{Pass: true, Reason: reason}, nil
+	}
+}
+
+func files(names ...string) Rule {
+	return func(app App, _ Group) (Outcome, error) {
+		for _, name := range names {
+			exists, err := app.exists(name)
+			if err != nil {
+				return Outcome{}, err
+			}
+
+			if !exists {
+				return Outcome{Reason: fmt.Sprintf("%s not found", name)}, nil
+			}
+		}
+
+		return Outcome{Pass: true, Reason: fmt.Sprintf("%s found", strings.Join(names, " and "))}, nil
+	}
+}
+
+func anyFile(names ...string) Rule {
+	return func(app App, _ Group) (Outcome, error) {
+		for _, name := range names {
+			exists, err := app.exists(name)
+			if err != nil {
+				return Outcome{}, err
+			}
+
+			if exists {
+				return Outcome{Pass: true, Reason: fmt.Sprintf("%s found", name)}, nil
+			}
+		}
+
+		return Outcome{Reason: fmt.Sprintf("none of %s found", strings.Join(names, ", "))}, nil
+	}
+}
+
+func envSet(name string) Rule {
+	return func(app App, _ Group) (Outcome, error) {
+		if app.Env[name] == "" {
+			return Outcome{Reason: fmt.Sprintf("%s is not set", name)}, nil
+		}
+
+		return Outcome{Pass: true, Reason: fmt.Sprintf("%s is set", name)}, nil
+	}
+}
+
+func envEquals(name, value string) Rule {
+	return func(app App, _ Group) (Outcome, error) {
+		if app.Env[name] != value {
+			return Outcome{Reason: fmt.Sprintf("%s is not %q", name, value)}, nil
+		}
+
+		return Outcome{Pass: true, Reason: fmt.Sprintf("%s is %q", name, value)}, nil
+	}
+}
+
+func envPrefix(prefix string) Rule {
+	return func(app App, _ Group) (Outcome, error) {
+		var names []string
+		for name := range app.Env {
+			if strings.HasPrefix(name, prefix) {
+				names = append(names, name)
+			}
+		}
+
+		if len(names) == 0 {
+			return Outcome{Reason: fmt.Sprintf("no %s* variables are set", prefix)}, nil
+		}
+
+		sort.Strings(names)
+		return Outcome{Pass: true, Reason: fmt.Sprintf("%s is set", strings.Join(names, ", "))}, nil
+	}
+}
+
+func all(rules ...Rule) Rule {
+	return func(app App, group Group) (Outcome, error) {
+		var reasons []string
+		for _, rule := range rules {
+			outcome, err := rule(app, group)
+			if err != nil || !outcome.Pass {
+				return outcome, err
+			}
+
+			reasons = append(reasons, outcome.Reason)
+		}
+
+		return Outcome{Pass: true, Reason: strings.Join(reasons, ", ")}, nil
+	}
+}
+
+func anyOf(rules ...Rule) Rule {
+	return func(app App, group Group) (Outcome, error) {
+		var reasons []string
+		for _, rule := range rules {
+			outcome, err := rule(app, group)
+			if err != nil || outcome.Pass {
+				return outcome, err
+			}
+
+			reasons = append(reasons, outcome.Reason)
+		}
+
+		return Outcome{Reason: strings.Join(reasons, " and ")}, nil
+	}
+}
+
+func server(name, config string) Rule {
+	return func(app App, group Group) (Outcome, error) {
+		exists, err := app.exists(config)
+		if err != nil {
+			return Outcome{}, err
+		}
+
+		if exists {
+			return Outcome{Pass: true, Reason: fmt.Sprintf("%s found", config)}, nil
+		}
+
+		if app.Env["BP_WEB_SERVER"] == name {
+			return Outcome{Pass: true, Reason: fmt.Sprintf("BP_WEB_SERVER is %q", name)}, nil
+		}
+
+		if name == "nginx" && group.contains("paketo-buildpacks/nginx-config") {
+			generated, err := nginxConfig(app, group)
+			if err != nil {
+				return Outcome{}, err
+			}
+
+			if generated.Pass {
+				return Outcome{Pass: true, Reason: fmt.Sprintf("%s is generated by paketo-buildpacks/nginx-config", config)}, nil
+			}
+		}
+
+		return Outcome{Reason: fmt.Sprintf("%s not found", config)}, nil
+	}
+}
+
+func webServerSelector(app App, group Group) (Outcome, error) {
+	name := app.Env["BP_WEB_SERVER"]
+	if name == "" {
+		return Outcome{Pass: true, Reason: "BP_WEB_SERVER is not set, any web server satisfies the selection"}, nil
+	}
+
+	id, ok := servers[name]
+	if !ok {
+		return Outcome{Reason: fmt.Sprintf("unsupported BP_WEB_SERVER value %q", name)}, nil
+	}
+
+	if !group.contains(id) {
+		return Outcome{Reason: fmt.Sprintf("BP_WEB_SERVER is %q but the group does not contain %s", name, id)}, nil
+	}
+
+	return Outcome{Pass: true, Reason: fmt.Sprintf("BP_WEB_SERVER is %q", name)}, nil
+}
+
+func nginxConfig(app App, _ Group) (Outcome, error) {
+	if name := app.Env["BP_WEB_SERVER"]; name != "" {
+		return Outcome{Reason: fmt.Sprintf("BP_WEB_SERVER is %q", name)}, nil
+	}
+
+	for _, config := range []string{"nginx.conf", "httpd.conf", "Caddyfile"} {
+		exists, err := app.exists(config)
+		if err != nil {
+			return Outcome{}, err
+		}
+
+		if exists {
+			return Outcome{Reason: fmt.Sprintf("%s found", config)}, nil
+		}
+	}
+
+	return files("package.json")(app, nil)
+}
+
+// webServerConfig builds the rule of the web-server-config buildpack from the
+// settings it exports, so that a new feature is simulated without a change
+// here.
+func webServerConfig() Rule {
+	var rules []Rule
+	for _, setting := range webserverconfig.Settings {
+		if prefix, ok := strings.CutSuffix(setting, "*"); ok {
+			rules = append(rules, envPrefix(prefix))
+			continue
+		}
+
+		rules = append(rules, envSet(setting))
+	}
+
+	return anyOf(append(rules, files(webserverconfig.ConfigFile))...)
+}
+
+func caCertificates(app App, _ Group) (Outcome, error) {
+	root := app.Env["SERVICE_BINDING_ROOT"]
+	if root == "" {
+		return Outcome{Reason: "SERVICE_BINDING_ROOT is not set"}, nil
+	}
+
+	types, err := filepath.Glob(filepath.Join(root, "*", "type"))
+	if err != nil {
+		return Outcome{}, err
+	}
+
+	for _, path := range types {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return Outcome{}, err
+		}
+
+		if strings.TrimSpace(string(content)) == "ca-certificates" {
+			return Outcome{Pass: true, Reason: fmt.Sprintf("ca-certificates binding found in %s", filepath.Dir(path))}, nil
+		}
+	}
+
+	return Outcome{Reason: "no ca-certificates binding found"}, nil
+}
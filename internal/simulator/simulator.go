@@ -0,0 +1,114 @@
+// Package simulator evaluates the composite buildpack order against an app
+// directory without running the lifecycle, to explain which group would be
+// selected and why every earlier group was not.
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/paketo-buildpacks/web-servers/internal/manifest"
+)
+
+// App is the input to detection: the application source and the build-time
+// environment.
+type App struct {
+	Dir string
+	Env map[string]string
+}
+
+func (a App) exists(name string) (bool, error) {
+	_, err := os.Stat(filepath.Join(a.Dir, name))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Group is a single [[order]] group.
+type Group []manifest.GroupEntry
+
+func (g Group) contains(id string) bool {
+	for _, entry := range g {
+		if entry.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Outcome is the result of detecting a single component.
+type Outcome struct {
+	Pass   bool
+	Reason string
+}
+
+// EntryResult pairs a group entry with its detection outcome.
+type EntryResult struct {
+	manifest.GroupEntry
+	Outcome
+}
+
+// GroupResult is the detection outcome of an order group. Index is the
+// 1-based position of the group in buildpack.toml.
+type GroupResult struct {
+	Index   int
+	Pass    bool
+	Entries []EntryResult
+}
+
+// Failures returns the required entries that caused the group to fail.
+func (r GroupResult) Failures() []EntryResult {
+	var failures []EntryResult
+	for _, entry := range r.Entries {
+		if !entry.Optional && !entry.Pass {
+			failures = append(failures, entry)
+		}
+	}
+
+	return failures
+}
+
+// Simulate detects each order in turn, like the lifecycle does, and returns
+// the results up to and including the first group that passes. The final
+// result has Pass set to false when no group passes.
+func Simulate(orders []manifest.Order, app App) ([]GroupResult, error) {
+	var results []GroupResult
+	for i, order := range orders {
+		group := Group(order.Group)
+		result := GroupResult{Index: i + 1, Pass: true}
+
+		for _, entry := range group {
+			rule, ok := Rules[entry.ID]
+			if !ok {
+				return nil, fmt.Errorf("no detection rule for %s", entry.ID)
+			}
+
+			outcome, err := rule(app, group)
+			if err != nil {
+				return nil, fmt.Errorf("failed to detect %s: %w", entry.ID, err)
+			}
+
+			if !entry.Optional && !outcome.Pass {
+				result.Pass = false
+			}
+
+			result.Entries = append(result.Entries, EntryResult{GroupEntry: entry, Outcome: outcome})
+		}
+
+		results = append(results, result)
+		if result.Pass {
+			break
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,235 @@
+package simulator_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	webserverconfig "github.com/paketo-buildpacks/web-servers/buildpacks/web-server-config"
+	"github.com/paketo-buildpacks/web-servers/internal/manifest"
+	"github.com/paketo-buildpacks/web-servers/internal/simulator"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testSimulate(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		orders []manifest.Order
+	)
+
+	it.Before(func() {
+		m, err := manifest.Load(filepath.Join("..", ".."))
+		Expect(err).NotTo(HaveOccurred())
+
+		orders = m.Buildpack.Order
+	})
+
+	// required returns the IDs of the non-optional buildpacks in the group
+	// that passed, which identifies it independently of its position.
+	required := func(results []simulator.GroupResult) []string {
+		last := results[len(results)-1]
+		Expect(last.Pass).To(BeTrue(), "no group passed")
+
+		var ids []string
+		for _, entry := range last.Entries {
+			if !entry.Optional {
+				ids = append(ids, entry.ID)
+			}
+		}
+
+		return ids
+	}
+
+	context("against the integration test apps", func() {
+		for _, tc := range []struct {
+			app      string
+			env      map[string]string
+			expected []string
+		}{
+			{
+				app:      "nginx",
+				expected: []string{"paketo-buildpacks/web-server-selector", "paketo-buildpacks/nginx", "paketo-buildpacks/source-removal"},
+			},
+			{
+				app:      "httpd",
+				expected: []string{"paketo-buildpacks/web-server-selector", "paketo-buildpacks/httpd", "paketo-buildpacks/source-removal"},
+			},
+			{
+				app:      "caddy",
+				expected: []string{"paketo-buildpacks/web-server-selector", "paketo-community/caddy", "paketo-buildpacks/source-removal"},
+			},
+			{
+				app:      "nginx-and-httpd",
+				expected: []string{"paketo-buildpacks/web-server-selector", "paketo-buildpacks/nginx", "paketo-buildpacks/source-removal"},
+			},
+			{
+				app:      "nginx-and-httpd",
+				env:      map[string]string{"BP_WEB_SERVER": "httpd"},
+				expected: []string{"paketo-buildpacks/web-server-selector", "paketo-buildpacks/httpd", "paketo-buildpacks/source-removal"},
+			},
+			{
+				app:      "hugo-nginx",
+				expected: []string{"paketo-community/hugo", "paketo-buildpacks/web-server-selector", "paketo-buildpacks/nginx", "paketo-buildpacks/source-removal"},
+			},
+			{
+				app:      "hugo-httpd",
+				expected: []string{"paketo-community/hugo", "paketo-buildpacks/web-server-selector", "paketo-buildpacks/httpd", "paketo-buildpacks/source-removal"},
+			},
+			{
+				app:      "yarn-nginx-javascript-frontend",
+				env:      map[string]string{"BP_NODE_RUN_SCRIPTS": "build"},
+				expected: []string{"paketo-buildpacks/node-engine", "paketo-buildpacks/yarn", "paketo-buildpacks/yarn-install", "paketo-buildpacks/node-run-script", "paketo-buildpacks/web-server-selector", "paketo-buildpacks/nginx", "paketo-buildpacks/source-removal"},
+			},
+			{
+				app:      "pnpm-httpd-javascript-frontend",
+				env:      map[string]string{"BP_NODE_RUN_SCRIPTS": "build"},
+				expected: []string{"paketo-buildpacks/node-engine", "paketo-buildpacks/pnpm", "paketo-buildpacks/pnpm-install", "paketo-buildpacks/node-run-script", "paketo-buildpacks/web-server-selector", "paketo-buildpacks/httpd", "paketo-buildpacks/source-removal"},
+			},
+			{
+				app:      "bun-nginx-javascript-frontend",
+				env:      map[string]string{"BP_BUN_RUN_SCRIPTS": "build"},
+				expected: []string{"paketo-community/bun", "paketo-community/bun-install", "paketo-community/bun-run-script", "paketo-buildpacks/web-server-selector", "paketo-buildpacks/nginx", "paketo-buildpacks/source-removal"},
+			},
+			{
+				app:      "npm-httpd-javascript-frontend",
+				env:      map[string]string{"BP_NODE_RUN_SCRIPTS": "build"},
+				expected: []string{"paketo-buildpacks/node-engine", "paketo-buildpacks/npm-install", "paketo-buildpacks/node-run-script", "paketo-buildpacks/web-server-selector", "paketo-buildpacks/httpd", "paketo-buildpacks/source-removal"},
+			},
+			{
+				app:      "npm-caddy-javascript-frontend",
+				env:      map[string]string{"BP_NODE_RUN_SCRIPTS": "build"},
+				expected: []string{"paketo-buildpacks/node-engine", "paketo-buildpacks/npm-install", "paketo-buildpacks/node-run-script", "paketo-buildpacks/web-server-selector", "paketo-community/caddy", "paketo-buildpacks/source-removal"},
+			},
+			{
+				app:      "npm-zero-config-javascript-frontend",
+				env:      map[string]string{"BP_NODE_RUN_SCRIPTS": "build"},
+				expected: []string{"paketo-buildpacks/node-engine", "paketo-buildpacks/npm-install", "paketo-buildpacks/node-run-script", "paketo-buildpacks/web-server-selector", "paketo-buildpacks/nginx", "paketo-buildpacks/source-removal"},
+			},
+			{
+				// Without BP_NODE_RUN_SCRIPTS the frontend is served as-is.
+				app:      "npm-nginx-javascript-frontend",
+				expected: []string{"paketo-buildpacks/web-server-selector", "paketo-buildpacks/nginx", "paketo-buildpacks/source-removal"},
+			},
+		} {
+			tc := tc
+			it("selects the expected group for "+tc.app, func() {
+				results, err := simulator.Simulate(orders, simulator.App{
+					Dir: filepath.Join("..", "..", "integration", "testdata", tc.app),
+					Env: tc.env,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(required(results)).To(Equal(tc.expected), "env: %v", tc.env)
+			})
+		}
+	})
+
+	it("explains why every earlier group failed", func() {
+		results, err := simulator.Simulate(orders, simulator.App{
+			Dir: filepath.Join("..", "..", "integration", "testdata", "nginx-and-httpd"),
+			Env: map[string]string{"BP_WEB_SERVER": "httpd"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, result := range results[:len(results)-1] {
+			Expect(result.Pass).To(BeFalse())
+			Expect(result.Failures()).NotTo(BeEmpty(), "group %d", result.Index)
+		}
+
+		var nginx simulator.GroupResult
+		for _, result := range results {
+			for _, entry := range result.Entries {
+				if entry.ID == "paketo-buildpacks/nginx" {
+					nginx = result
+				}
+			}
+		}
+		Expect(nginx.Failures()).To(ConsistOf(simulator.EntryResult{
+			GroupEntry: manifest.GroupEntry{ID: "paketo-buildpacks/web-server-selector", Version: "0.1.0"},
+			Outcome:    simulator.Outcome{Reason: `BP_WEB_SERVER is "httpd" but the group does not contain paketo-buildpacks/httpd`},
+		}))
+	})
+
+	it("reports optional buildpacks without failing the group", func() {
+		results, err := simulator.Simulate(orders, simulator.App{
+			Dir: filepath.Join("..", "..", "integration", "testdata", "nginx"),
+			Env: map[string]string{
+				"BPE_SOME_VARIABLE":      "some-value",
+				"BP_IMAGE_LABELS":        "some-label=some-value",
+				"BP_LIVE_RELOAD_ENABLED": "true",
+				"SERVICE_BINDING_ROOT":   filepath.Join("..", "..", "integration", "testdata", "ca_cert_apps"),
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		outcomes := map[string]simulator.Outcome{}
+		for _, entry := range results[len(results)-1].Entries {
+			outcomes[entry.ID] = entry.Outcome
+		}
+
+		Expect(outcomes["paketo-buildpacks/ca-certificates"].Pass).To(BeTrue())
+		Expect(outcomes["paketo-buildpacks/watchexec"].Pass).To(BeTrue())
+		Expect(outcomes["paketo-buildpacks/environment-variables"]).To(Equal(simulator.Outcome{Pass: true, Reason: "BPE_SOME_VARIABLE is set"}))
+		Expect(outcomes["paketo-buildpacks/image-labels"].Pass).To(BeTrue())
+		Expect(outcomes["paketo-buildpacks/procfile"]).To(Equal(simulator.Outcome{Reason: "Procfile not found"}))
+	})
+
+	it("detects web-server-config from each of its settings and its config file", func() {
+		rule := simulator.Rules["paketo-buildpacks/web-server-config"]
+
+		outcome, err := rule(simulator.App{Dir: t.TempDir()}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(outcome.Pass).To(BeFalse())
+
+		for _, setting := range webserverconfig.Settings {
+			name := strings.TrimSuffix(setting, "*")
+			if name != setting {
+				name += "SOME_NAME"
+			}
+
+			outcome, err := rule(simulator.App{Dir: t.TempDir(), Env: map[string]string{name: "some-value"}}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(outcome.Pass).To(BeTrue(), name)
+		}
+
+		dir := t.TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, webserverconfig.ConfigFile), nil, 0600)).To(Succeed())
+
+		outcome, err = rule(simulator.App{Dir: dir}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(outcome).To(Equal(simulator.Outcome{Pass: true, Reason: "web-servers.toml found"}))
+	})
+
+	context("when no group passes", func() {
+		it("returns every group as failed", func() {
+			results, err := simulator.Simulate(orders, simulator.App{Dir: t.TempDir()})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(len(orders)))
+			Expect(results[len(results)-1].Pass).To(BeFalse())
+		})
+	})
+
+	context("failure cases", func() {
+		context("when a group contains a buildpack without a detection rule", func() {
+			it("returns an error", func() {
+				_, err := simulator.Simulate([]manifest.Order{
+					{Group: []manifest.GroupEntry{{ID: "some-org/unknown", Version: "1.2.3"}}},
+				}, simulator.App{Dir: t.TempDir()})
+				Expect(err).To(MatchError("no detection rule for some-org/unknown"))
+			})
+		})
+
+		context("when the app directory cannot be inspected", func() {
+			it("returns an error", func() {
+				dir := t.TempDir()
+				Expect(os.WriteFile(filepath.Join(dir, "file"), nil, 0600)).To(Succeed())
+
+				_, err := simulator.Simulate(orders, simulator.App{Dir: filepath.Join(dir, "file")})
+				Expect(err).To(MatchError(ContainSubstring("failed to detect")))
+			})
+		})
+	})
+}
@@ -15,8 +15,9 @@ source "${ROOT_DIR}/scripts/.util/tools.sh"
 source "${ROOT_DIR}/scripts/.util/print.sh"
 
 function main {
-  local version output token flags
+  local version output token flags dependencies
   token=""
+  dependencies=""
 
   while [[ "${#}" != 0 ]]; do
     case "${1}" in
@@ -40,6 +41,11 @@ function main {
         shift 2
         ;;
 
+      --dependencies|-d)
+        dependencies="$(cd "${2}" && pwd)"
+        shift 2
+        ;;
+
       --help|-h)
         shift 1
         usage
@@ -71,8 +77,9 @@ function main {
   tools::install "${token}"
 
   buildpack::archive "${version}"
+  buildpacks::first-party::archive
   buildpack::release::archive
-  buildpackage::create "${output}" "${flags[@]}"
+  buildpackage::create "${output}" "${dependencies}" "${flags[@]}"
 }
 
 function usage() {
@@ -86,6 +93,7 @@ OPTIONS
   --version <version>  -v <version>  specifies the version number to use when packaging the buildpack
   --output <output>    -o <output>   location to output the packaged buildpackage artifact (default: ${ROOT_DIR}/build/buildpackage.cnb)
   --token <token>                    Token used to download assets from GitHub (e.g. jam, pack, etc) (optional)
+  --dependencies <dir> -d <dir>      resolve component buildpacks from OCI image layouts and .cnb files in <dir> instead of pulling them (optional)
 USAGE
 }
 
@@ -130,6 +138,51 @@ function buildpack::archive() {
     --output "${BUILD_DIR}/buildpack.tgz"
 }
 
+function buildpacks::first-party::archive() {
+  local dir name id version targets
+  targets=$(yj -tj < "${ROOT_DIR}/package.toml" | jq -r '.targets[] | "\(.os)/\(.arch)"')
+
+  for dir in "${ROOT_DIR}"/buildpacks/*/; do
+    dir="${dir%/}"
+    name="$(basename "${dir}")"
+    id="$(yj -tj < "${dir}/buildpack.toml" | jq -r .buildpack.id)"
+
+    # First-party buildpacks are versioned by the pin in the composite order
+    version="$(yj -tj < "${ROOT_DIR}/buildpack.toml" | jq -r --arg id "${id}" '[.order[].group[] | select(.id == $id) | .version] | first')"
+    if [[ "${version}" == "null" ]]; then
+      util::print::error "${id} is not referenced by any order in buildpack.toml"
+    fi
+
+    util::print::title "Packaging ${id} ${version} into ${BUILD_DIR}/${name}.tgz..."
+
+    for target in ${targets}; do
+      util::print::info "Building ${name} for ${target}..."
+
+      mkdir -p "${dir}/${target}/bin"
+
+      # Every main package is built into bin/, so helpers such as exec.d
+      # binaries ship alongside run
+      for cmd in "${dir}"/*/main.go; do
+        cmd="$(dirname "${cmd}")"
+        GOOS="${target%/*}" GOARCH="${target#*/}" CGO_ENABLED=0 \
+          go build \
+            -ldflags="-s -w" \
+            -o "${dir}/${target}/bin/$(basename "${cmd}")" \
+            "${cmd}"
+      done
+
+      ln -sf run "${dir}/${target}/bin/detect"
+      ln -sf run "${dir}/${target}/bin/build"
+    done
+
+    jam pack \
+      --buildpack "${dir}/buildpack.toml" \
+      --version "${version}" \
+      --offline \
+      --output "${BUILD_DIR}/${name}.tgz"
+  done
+}
+
 function buildpack::release::archive() {
   local tmp_dir
 
@@ -148,6 +201,7 @@ It contains the following files:
 * `package.toml` - this is needed because it contains the dependencies (and URIs) that let pack know where to find the buildpacks referenced in `buildpack.toml`.
   * `package.toml` can contain targets (platforms) for multi-arch support
 * `build/buildpack.tgz` - this is added because it is referenced in `package.toml` by some buildpacks
+* `build/<name>.tgz` - the first-party buildpacks from this repository, also referenced in `package.toml`
 
 ## package locally
 
@@ -172,7 +226,7 @@ pack buildpack package SOME-REGISTRY/mybuildpack:SOME-VERSION --format image --c
 README_EOF
 
   mkdir -p $tmp_dir/build
-  cp ${BUILD_DIR}/buildpack.tgz $tmp_dir/build
+  cp ${BUILD_DIR}/*.tgz $tmp_dir/build
   cp ${ROOT_DIR}/package.toml $tmp_dir/
   # add the buildpack.toml from the tgz file because it has the version populated
   tar -xzf ${BUILD_DIR}/buildpack.tgz -C $tmp_dir/ buildpack.toml
@@ -182,9 +236,10 @@ README_EOF
 }
 
 function buildpackage::create() {
-  local output flags release_archive_path tmp_dir
+  local output dependencies flags release_archive_path tmp_dir
   output="${1}"
-  flags=("${@:2}")
+  dependencies="${2}"
+  flags=("${@:3}")
   release_archive_path="${BUILD_DIR}/buildpack-release-artifact.tgz"
 
   util::print::title "Packaging buildpack..."
@@ -206,6 +261,21 @@ function buildpackage::create() {
   # Use the local architecture to support running locally and in CI, which will be linux/amd64 by default.
   arch=$(util::tools::arch)
 
+  # Air-gapped builds stage every component buildpack from local artifacts and
+  # rewrite package.toml to reference them, so pack never reaches a registry.
+  if [[ -n "${dependencies}" ]]; then
+    util::print::info "Staging dependencies from ${dependencies}..."
+    (
+      cd "${ROOT_DIR}"
+      go run ./cmd/stage-dependencies \
+        --root "${tmp_dir}" \
+        --source "${dependencies}" \
+        --target "linux/${arch}"
+    )
+
+    args+=("--pull-policy" "never")
+  fi
+
   # If package.toml has no targets we must specify one on the command line, otherwise pack will complain.
   # This is here for backward compatibility but eventually all package.toml files should have targets defined.
   if cat package.toml | yj -tj | jq -r .targets | grep -q null; then
@@ -13,8 +13,9 @@ source "${ROOT_DIR}/scripts/.util/tools.sh"
 source "${ROOT_DIR}/scripts/.util/print.sh"
 
 function main {
-  local archive_path image_ref token
+  local archive_path image_ref token layout
   token=""
+  layout=""
 
   while [[ "${#}" != 0 ]]; do
     case "${1}" in
@@ -33,6 +34,12 @@ function main {
       shift 2
       ;;
 
+    --layout | -l)
+      mkdir -p "${2}"
+      layout="$(cd "${2}" && pwd)"
+      shift 2
+      ;;
+
     --help | -h)
       shift 1
       usage
@@ -50,7 +57,7 @@ function main {
     esac
   done
 
-  if [[ -z "${image_ref:-}" ]]; then
+  if [[ -z "${image_ref:-}" && -z "${layout}" ]]; then
     usage
     util::print::error "--image-ref is required"
   fi
@@ -66,7 +73,11 @@ function main {
 
   tools::install "${token}"
 
-  buildpack::publish "${image_ref}" "${archive_path}"
+  if [[ -n "${layout}" ]]; then
+    buildpack::layout "${layout}" "${image_ref:-}" "${archive_path}"
+  else
+    buildpack::publish "${image_ref}" "${archive_path}"
+  fi
 }
 
 function usage() {
@@ -76,7 +87,8 @@ Publishes a composite buildpack to a registry.
 OPTIONS
   -a, --archive-path <filepath>       Path to the buildpack release artifact (default: ${ROOT_DIR}/build/buildpack-release-artifact.tgz) (optional)
   -h, --help                          Prints the command usage
-  -i, --image-ref <ref>               List of image reference to publish to (required)
+  -i, --image-ref <ref>               List of image reference to publish to (required unless --layout is given)
+  -l, --layout <dir>                  Write the buildpack to an OCI image layout directory instead of a registry, recording --image-ref as its ref name (optional)
   -t, --token <token>                 Token used to download assets from GitHub (e.g. jam, pack, etc) (optional)
 
 USAGE
@@ -140,4 +152,53 @@ function buildpack::publish() {
   rm -rf $tmp_dir
 }
 
+function buildpack::layout() {
+  local layout image_ref archive_path tmp_dir
+  layout="${1}"
+  image_ref="${2}"
+  archive_path="${3}"
+
+  util::print::title "Writing composite buildpack to OCI image layout..."
+
+  util::print::info "Extracting archive..."
+  tmp_dir=$(mktemp -d -p $ROOT_DIR)
+  tar -xvf $archive_path -C $tmp_dir
+
+  current_dir=$(pwd)
+  cd $tmp_dir
+
+  targets=""
+  if cat package.toml | yj -tj | jq -r .targets | grep -q null; then
+    arch=$(util::tools::arch)
+    targets="--target linux/${arch}"
+    echo "package.toml has no targets so ${targets} will be used"
+  fi
+
+  # With targets, pack writes one buildpackage-<os>-<arch>.cnb per target
+  # next to the requested output; without, it writes the output itself.
+  pack \
+    buildpack package "${tmp_dir}/buildpackage.cnb" \
+    --config package.toml \
+    --format file \
+    ${targets}
+
+  cd $current_dir
+
+  util::print::info "Assembling ${layout}..."
+  (
+    cd "${ROOT_DIR}"
+    go run ./cmd/assemble-layout \
+      --output "${layout}" \
+      --ref "${image_ref}" \
+      "${tmp_dir}"/buildpackage*.cnb
+
+    util::print::info "Verifying ${layout}..."
+    go run ./cmd/verify-layout \
+      --root "${tmp_dir}" \
+      --layout "${layout}"
+  )
+
+  rm -rf $tmp_dir
+}
+
 main "${@:-}"